@@ -545,7 +545,7 @@ func (app *App) GetTopMembers(ctx context.Context, req *api.GetTopMembersRequest
 	err := withSegment("Model", ctx, func() error {
 		var err error
 		lg.Debug("Getting top members.")
-		members, err = app.Leaderboards.GetLeaders(ctx, req.LeaderboardId, pageSize, pageNumber, order)
+		members, err = app.Leaderboards.GetLeaders(ctx, req.LeaderboardId, pageSize, pageNumber, order, false)
 
 		if err != nil {
 			lg.Error("Getting top members failed.", zap.Error(err))
@@ -643,7 +643,7 @@ func (app *App) GetMembers(ctx context.Context, req *api.GetMembersRequest) (*ap
 	err := withSegment("Model", ctx, func() error {
 		var err error
 		lg.Debug("Getting members.", zap.String("ids", req.Ids))
-		members, err = app.Leaderboards.GetMembers(ctx, req.LeaderboardId, memberIDs, order, req.ScoreTTL)
+		members, err = app.Leaderboards.GetMembers(ctx, req.LeaderboardId, memberIDs, order, req.ScoreTTL, false)
 
 		if err != nil {
 			lg.Error("Getting members failed.", zap.Error(err))
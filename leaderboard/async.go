@@ -0,0 +1,151 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// defaultAsyncWorkers is how many goroutines apply asynchronous score updates
+const defaultAsyncWorkers = 4
+
+// defaultAsyncBufferSize is how many asynchronous score updates may be queued before callers block
+const defaultAsyncBufferSize = 1000
+
+// asyncFlushPollInterval is how often Flush checks if all asynchronous score updates were applied
+const asyncFlushPollInterval = time.Millisecond
+
+// ScoreResult is the outcome of an asynchronous score update
+type ScoreResult struct {
+	Member *Member
+	Err    error
+}
+
+type asyncUpdate struct {
+	ctx           context.Context
+	leaderboardID string
+	memberID      string
+	score         int64
+	scoreTTL      string
+	result        chan ScoreResult
+}
+
+// WithAsyncWorkers sets how many goroutines apply the score updates made by SetMemberScoreAsync
+func WithAsyncWorkers(workers int) ClientOption {
+	return func(c *Client) {
+		c.asyncWorkers = workers
+	}
+}
+
+// WithAsyncBufferSize sets how many score updates made by SetMemberScoreAsync may be queued before
+// new ones block
+func WithAsyncBufferSize(size int) ClientOption {
+	return func(c *Client) {
+		c.asyncBufferSize = size
+	}
+}
+
+// SetMemberScoreAsync queues an update of the score of the member with the given ID and returns right
+// away. The update is applied by a pool of worker goroutines, and its result is sent on the returned
+// channel, which callers may ignore. If the queue is full the call blocks until there is room for the
+// update, see PendingUpdates. The given context is used when the update is applied. The workers run
+// until StopAsyncWorkers is called.
+func (c *Client) SetMemberScoreAsync(ctx context.Context, leaderboardID string, memberID string, score int64,
+	scoreTTL string) <-chan ScoreResult {
+	updates := c.startAsyncWorkers()
+
+	result := make(chan ScoreResult, 1)
+	atomic.AddInt64(&c.asyncPending, 1)
+	updates <- &asyncUpdate{
+		ctx:           ctx,
+		leaderboardID: leaderboardID,
+		memberID:      memberID,
+		score:         score,
+		scoreTTL:      scoreTTL,
+		result:        result,
+	}
+	return result
+}
+
+// PendingUpdates returns how many score updates made by SetMemberScoreAsync were not applied yet
+func (c *Client) PendingUpdates() int {
+	return int(atomic.LoadInt64(&c.asyncPending))
+}
+
+// Flush waits until every score update made by SetMemberScoreAsync so far is applied, failing if the
+// context is done first
+func (c *Client) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(asyncFlushPollInterval)
+	defer ticker.Stop()
+
+	for c.PendingUpdates() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// StopAsyncWorkers waits until every queued score update is applied, as Flush does, and then stops the
+// workers started by SetMemberScoreAsync. It must not be called concurrently with SetMemberScoreAsync,
+// later calls to it start new workers. The workers are left running if the context is done first.
+func (c *Client) StopAsyncWorkers(ctx context.Context) error {
+	err := c.Flush(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.asyncUpdates != nil {
+		close(c.asyncUpdates)
+		c.asyncUpdates = nil
+	}
+	return nil
+}
+
+// startAsyncWorkers starts the workers that apply asynchronous score updates, if they were not started
+// yet, and returns their queue
+func (c *Client) startAsyncWorkers() chan<- *asyncUpdate {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.asyncUpdates == nil {
+		bufferSize := c.asyncBufferSize
+		if bufferSize <= 0 {
+			bufferSize = defaultAsyncBufferSize
+		}
+		workers := c.asyncWorkers
+		if workers <= 0 {
+			workers = defaultAsyncWorkers
+		}
+		c.asyncUpdates = make(chan *asyncUpdate, bufferSize)
+		for i := 0; i < workers; i++ {
+			go c.applyAsyncUpdates(c.asyncUpdates)
+		}
+	}
+	return c.asyncUpdates
+}
+
+func (c *Client) applyAsyncUpdates(updates <-chan *asyncUpdate) {
+	for update := range updates {
+		member, err := c.SetMemberScore(update.ctx, update.leaderboardID, update.memberID, update.score, false,
+			update.scoreTTL)
+		if err != nil {
+			member = nil
+		}
+		update.result <- ScoreResult{Member: member, Err: err}
+		atomic.AddInt64(&c.asyncPending, -1)
+	}
+}
@@ -0,0 +1,128 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Asynchronous Score Updates", func() {
+	var redisClient *extredis.Client
+	var leaderboards *Client
+
+	BeforeEach(func() {
+		var err error
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		leaderboards = NewClientWithRedis(redisClient, WithAsyncWorkers(2), WithAsyncBufferSize(10))
+	})
+
+	It("should apply the update and send its result", func() {
+		lbID := uuid.NewV4().String()
+		result := <-leaderboards.SetMemberScoreAsync(NewEmptyCtx(), lbID, "member-1", 100, "")
+		Expect(result.Err).NotTo(HaveOccurred())
+		Expect(result.Member.PublicID).To(Equal("member-1"))
+		Expect(result.Member.Score).To(Equal(int64(100)))
+		Expect(result.Member.Rank).To(Equal(1))
+	})
+
+	It("should apply every queued update when flushed", func() {
+		lbID := uuid.NewV4().String()
+		for i := 0; i < 50; i++ {
+			leaderboards.SetMemberScoreAsync(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), "")
+		}
+
+		err := leaderboards.Flush(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(leaderboards.PendingUpdates()).To(Equal(0))
+
+		total, err := leaderboards.TotalMembers(NewEmptyCtx(), lbID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(total).To(Equal(50))
+	})
+
+	It("should not wait when there are no pending updates", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		Expect(leaderboards.Flush(ctx)).To(Succeed())
+	})
+
+	It("should apply every queued update before stopping the workers", func() {
+		lbID := uuid.NewV4().String()
+		for i := 0; i < 20; i++ {
+			leaderboards.SetMemberScoreAsync(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), "")
+		}
+
+		err := leaderboards.StopAsyncWorkers(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(leaderboards.PendingUpdates()).To(Equal(0))
+		total, err := leaderboards.TotalMembers(NewEmptyCtx(), lbID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(total).To(Equal(20))
+
+		result := <-leaderboards.SetMemberScoreAsync(NewEmptyCtx(), lbID, "member-20", 20, "")
+		Expect(result.Err).NotTo(HaveOccurred())
+		Expect(leaderboards.StopAsyncWorkers(context.Background())).To(Succeed())
+	})
+
+	It("should not fail to stop workers that were never started", func() {
+		Expect(leaderboards.StopAsyncWorkers(context.Background())).To(Succeed())
+	})
+
+	It("should send the error of failed updates", func() {
+		redisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		faultyLeaderboards := NewClientWithRedis(redisClient)
+
+		result := <-faultyLeaderboards.SetMemberScoreAsync(NewEmptyCtx(), uuid.NewV4().String(), "member-1", 100, "")
+		Expect(result.Err).To(HaveOccurred())
+		Expect(result.Err.Error()).To(ContainSubstring("connection refused"))
+		Expect(result.Member).To(BeNil())
+		Expect(faultyLeaderboards.PendingUpdates()).To(Equal(0))
+	})
+
+	It("should fail to flush if the context is done first", func() {
+		release := make(chan struct{})
+		blockedLeaderboards := NewClientWithRedis(redisClient, WithAsyncWorkers(1), WithScoreValidator(
+			func(memberID string, currentScore, proposedScore int64) error {
+				<-release
+				return nil
+			}))
+		result := blockedLeaderboards.SetMemberScoreAsync(NewEmptyCtx(), uuid.NewV4().String(), "member-1", 100, "")
+		Expect(blockedLeaderboards.PendingUpdates()).To(Equal(1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := blockedLeaderboards.Flush(ctx)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+		err = blockedLeaderboards.StopAsyncWorkers(ctx)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+
+		close(release)
+		Expect((<-result).Err).NotTo(HaveOccurred())
+		Expect(blockedLeaderboards.Flush(context.Background())).To(Succeed())
+	})
+})
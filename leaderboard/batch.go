@@ -0,0 +1,94 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/topfreegames/extensions/redis/interfaces"
+	"github.com/topfreegames/podium/log"
+	"go.uber.org/zap"
+)
+
+// MemberQuery identifies a member of a given leaderboard
+type MemberQuery struct {
+	LeaderboardID string `json:"leaderboardID"`
+	MemberID      string `json:"memberID"`
+}
+
+// MemberResult is the score and rank of a queried member, Found is false if the member is not in the
+// leaderboard
+type MemberResult struct {
+	LeaderboardID string `json:"leaderboardID"`
+	MemberID      string `json:"memberID"`
+	Score         int64  `json:"score"`
+	Rank          int    `json:"rank"`
+	Found         bool   `json:"found"`
+}
+
+type memberQueryCmds struct {
+	score *redis.FloatCmd
+	rank  *redis.IntCmd
+}
+
+// GetMembersBatch returns the score and rank of members of several leaderboards in a single round
+// trip. Results are in the same order as the queries, and repeated queries are only sent once.
+func GetMembersBatch(redisClient interfaces.RedisClient, queries []MemberQuery, order string,
+	logger zap.Logger) ([]MemberResult, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	if len(queries) == 0 {
+		return []MemberResult{}, nil
+	}
+
+	pipe := redisClient.TxPipeline()
+	groups := map[string]map[string]*memberQueryCmds{}
+	for _, query := range queries {
+		group, ok := groups[query.LeaderboardID]
+		if !ok {
+			group = map[string]*memberQueryCmds{}
+			groups[query.LeaderboardID] = group
+		}
+		if _, ok := group[query.MemberID]; ok {
+			continue
+		}
+
+		cmds := &memberQueryCmds{score: pipe.ZScore(query.LeaderboardID, query.MemberID)}
+		if order == "desc" {
+			cmds.rank = pipe.ZRevRank(query.LeaderboardID, query.MemberID)
+		} else {
+			cmds.rank = pipe.ZRank(query.LeaderboardID, query.MemberID)
+		}
+		group[query.MemberID] = cmds
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of members batch failed: %v", err)
+	}
+
+	results := make([]MemberResult, len(queries))
+	for i, query := range queries {
+		cmds := groups[query.LeaderboardID][query.MemberID]
+		results[i] = MemberResult{LeaderboardID: query.LeaderboardID, MemberID: query.MemberID, Rank: -1}
+		if cmds.score.Err() != nil || cmds.rank.Err() != nil {
+			continue
+		}
+		results[i].Score = int64(cmds.score.Val())
+		results[i].Rank = int(cmds.rank.Val()) + 1
+		results[i].Found = true
+	}
+
+	log.D(logger, "Members batch retrieved.", func(cm log.CM) {
+		cm.Write(zap.Int("queries", len(queries)), zap.Int("leaderboards", len(groups)))
+	})
+	return results, nil
+}
@@ -0,0 +1,87 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+	. "github.com/topfreegames/podium/testing"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Members Batch", func() {
+	var redisClient *extredis.Client
+	var firstLbID, secondLbID string
+
+	BeforeEach(func() {
+		var err error
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		leaderboards := NewClientWithRedis(redisClient)
+
+		firstLbID = uuid.NewV4().String()
+		secondLbID = uuid.NewV4().String()
+		for i := 0; i < 5; i++ {
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), firstLbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), secondLbID, fmt.Sprintf("member-%d", i), int64(-i), false, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should return members of several leaderboards in query order", func() {
+		results, err := GetMembersBatch(redisClient.Client, []MemberQuery{
+			{LeaderboardID: firstLbID, MemberID: "member-4"},
+			{LeaderboardID: secondLbID, MemberID: "member-4"},
+			{LeaderboardID: firstLbID, MemberID: "invalid-member"},
+			{LeaderboardID: secondLbID, MemberID: "member-0"},
+			{LeaderboardID: firstLbID, MemberID: "member-4"},
+		}, "desc", NewMockLogger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(Equal([]MemberResult{
+			{LeaderboardID: firstLbID, MemberID: "member-4", Score: 4, Rank: 1, Found: true},
+			{LeaderboardID: secondLbID, MemberID: "member-4", Score: -4, Rank: 5, Found: true},
+			{LeaderboardID: firstLbID, MemberID: "invalid-member", Rank: -1},
+			{LeaderboardID: secondLbID, MemberID: "member-0", Score: 0, Rank: 1, Found: true},
+			{LeaderboardID: firstLbID, MemberID: "member-4", Score: 4, Rank: 1, Found: true},
+		}))
+	})
+
+	It("should return ranks in ascending order", func() {
+		results, err := GetMembersBatch(redisClient.Client, []MemberQuery{
+			{LeaderboardID: firstLbID, MemberID: "member-4"},
+			{LeaderboardID: secondLbID, MemberID: "member-4"},
+		}, "asc", NewMockLogger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results[0].Rank).To(Equal(5))
+		Expect(results[1].Rank).To(Equal(1))
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		faultyClient := redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		_, err := GetMembersBatch(faultyClient, []MemberQuery{
+			{LeaderboardID: firstLbID, MemberID: "member-4"},
+		}, "desc", NewMockLogger())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
@@ -0,0 +1,126 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/podium/leaderboard"
+)
+
+// benchmarkedOperations are the leaderboard operations run on each benchmark iteration, in order
+var benchmarkedOperations = []string{"SetMemberScore", "GetMember", "GetLeaders"}
+
+// OperationResult is the latency of a single benchmarked operation
+type OperationResult struct {
+	Count int64         `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+}
+
+// BenchmarkResult is the outcome of a benchmark run, keyed by operation name
+type BenchmarkResult struct {
+	Concurrency int                         `json:"concurrency"`
+	Iterations  int                         `json:"iterations"`
+	Duration    time.Duration               `json:"duration"`
+	Operations  map[string]*OperationResult `json:"operations"`
+}
+
+// RunBenchmark measures the latency of SetMemberScore, GetMember and GetLeaders against a temporary
+// leaderboard, which is removed once the benchmark finishes. The given number of iterations is split
+// among concurrency workers and each iteration runs all three operations. The first failing operation
+// stops the benchmark.
+func RunBenchmark(ctx context.Context, client *leaderboard.Client, concurrency int, iterations int) (*BenchmarkResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if iterations < 1 {
+		return nil, fmt.Errorf("Benchmark needs at least one iteration")
+	}
+
+	leaderboardID := fmt.Sprintf("benchmark:%s", uuid.NewV4().String())
+	defer client.DeleteLeaderboardFamily(context.Background(), leaderboardID)
+
+	timed := leaderboard.NewTimingMiddleware(client)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var benchErr error
+	iterationsCh := make(chan int)
+
+	start := time.Now()
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for iteration := range iterationsCh {
+				err := runIteration(ctx, timed, leaderboardID, iteration)
+				if err != nil {
+					errOnce.Do(func() {
+						benchErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < iterations; i++ {
+		select {
+		case iterationsCh <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(iterationsCh)
+	wg.Wait()
+
+	if benchErr != nil {
+		return nil, fmt.Errorf("Benchmark failed: %v", benchErr)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("Benchmark failed: %v", err)
+	}
+
+	result := &BenchmarkResult{
+		Concurrency: concurrency,
+		Iterations:  iterations,
+		Duration:    time.Since(start),
+		Operations:  map[string]*OperationResult{},
+	}
+	for _, operation := range benchmarkedOperations {
+		count, mean, p50, p95, p99 := timed.GetOperationStats(operation)
+		result.Operations[operation] = &OperationResult{Count: count, Mean: mean, P50: p50, P95: p95, P99: p99}
+	}
+	return result, nil
+}
+
+func runIteration(ctx context.Context, timed *leaderboard.TimingMiddleware, leaderboardID string, iteration int) error {
+	memberID := fmt.Sprintf("member-%d", iteration)
+	_, err := timed.SetMemberScore(ctx, leaderboardID, memberID, int64(iteration), false, "")
+	if err != nil {
+		return err
+	}
+	_, err = timed.GetMember(ctx, leaderboardID, memberID, "desc", false)
+	if err != nil {
+		return err
+	}
+	_, err = timed.GetLeaders(ctx, leaderboardID, 20, 1, "desc", false)
+	return err
+}
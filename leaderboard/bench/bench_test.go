@@ -0,0 +1,78 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package bench_test
+
+import (
+	"context"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard/bench"
+
+	extredis "github.com/topfreegames/extensions/redis"
+	"github.com/topfreegames/podium/leaderboard"
+)
+
+var _ = Describe("Benchmark", func() {
+	var redisClient *extredis.Client
+	var faultyRedisClient *extredis.Client
+
+	BeforeEach(func() {
+		var err error
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		faultyRedisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		faultyRedisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+	})
+
+	It("should report latency of each operation", func() {
+		keysBefore, err := redisClient.Client.Eval(`return redis.call("KEYS", ARGV[1])`, []string{}, "benchmark:*").Result()
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := RunBenchmark(context.Background(), leaderboard.NewClientWithRedis(redisClient), 4, 50)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Concurrency).To(Equal(4))
+		Expect(result.Iterations).To(Equal(50))
+		Expect(result.Duration).To(BeNumerically(">", 0))
+
+		for _, operation := range []string{"SetMemberScore", "GetMember", "GetLeaders"} {
+			Expect(result.Operations).To(HaveKey(operation))
+			stats := result.Operations[operation]
+			Expect(stats.Count).To(Equal(int64(50)))
+			Expect(stats.P50).To(BeNumerically(">", 0))
+			Expect(stats.P95).To(BeNumerically(">=", stats.P50))
+			Expect(stats.P99).To(BeNumerically(">=", stats.P95))
+		}
+
+		keysAfter, err := redisClient.Client.Eval(`return redis.call("KEYS", ARGV[1])`, []string{}, "benchmark:*").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keysAfter).To(HaveLen(len(keysBefore.([]interface{}))))
+	})
+
+	It("should fail without iterations", func() {
+		_, err := RunBenchmark(context.Background(), leaderboard.NewClientWithRedis(redisClient), 4, 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		_, err := RunBenchmark(context.Background(), leaderboard.NewClientWithRedis(faultyRedisClient), 4, 10)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
@@ -0,0 +1,73 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package bench_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+	"github.com/topfreegames/podium/leaderboard"
+)
+
+// scoreRangeSize is the number of members returned by each score range lookup
+const scoreRangeSize = 100
+
+func newScoreRangeLeaderboard(b *testing.B) (*leaderboard.Client, string) {
+	config := viper.New()
+	config.Set("redis.url", "redis://localhost:1234/0")
+	config.Set("redis.connectionTimeout", 200)
+	redisClient, err := extredis.NewClient("redis", config)
+	if err != nil {
+		b.Fatal(err)
+	}
+	client := leaderboard.NewClientWithRedis(redisClient)
+
+	lbID := uuid.NewV4().String()
+	members := make(leaderboard.Members, 10000)
+	for i := range members {
+		members[i] = &leaderboard.Member{PublicID: fmt.Sprintf("member-%d", i), Score: int64(i)}
+	}
+	err = client.SetMembersScore(context.Background(), lbID, members, false, "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return client, lbID
+}
+
+func BenchmarkGetMembersByScoreRange(b *testing.B) {
+	client, lbID := newScoreRangeLeaderboard(b)
+	defer client.RemoveLeaderboard(context.Background(), lbID)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := client.GetMembersByScoreRange(context.Background(), lbID, 5000, 5000+scoreRangeSize-1, 0, 0, "desc")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetMembersByScorePipeline(b *testing.B) {
+	client, lbID := newScoreRangeLeaderboard(b)
+	defer client.RemoveLeaderboard(context.Background(), lbID)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := client.GetMembersByScorePipeline(context.Background(), lbID, 5000, 5000+scoreRangeSize-1, 0, 0, "desc")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// leadersCache keeps the top members of warmed up leaderboards, keyed by leaderboard ID
+var leadersCache sync.Map
+
+type leadersCacheEntry struct {
+	members  []Member
+	expireAt time.Time
+}
+
+// WarmupLeaderboard fetches the top topN members of a leaderboard in descending order and keeps them
+// in memory for ttl, so GetLeaders calls for its first page in descending order do not hit Redis.
+// Writes through this process invalidate the cache, but writes made by other processes are only
+// seen once the entries expire.
+func (c *Client) WarmupLeaderboard(ctx context.Context, leaderboardID string, topN int, ttl time.Duration) error {
+	members, err := getMembersByRange(c.redisWithTracing(ctx), leaderboardID, 0, topN-1, "desc")
+	if err != nil {
+		return err
+	}
+
+	entry := &leadersCacheEntry{members: make([]Member, len(members)), expireAt: time.Now().Add(ttl)}
+	for i, member := range members {
+		entry.members[i] = *member
+	}
+	leadersCache.Store(leaderboardID, entry)
+	return nil
+}
+
+// InvalidateCache removes all cached members of a leaderboard
+func (c *Client) InvalidateCache(leaderboardID string) {
+	leadersCache.Delete(leaderboardID)
+}
+
+// cachedLeaders returns the first pageSize members of a leaderboard in descending order if all of
+// them are cached and not expired
+func cachedLeaders(leaderboardID string, pageSize int) ([]*Member, bool) {
+	value, ok := leadersCache.Load(leaderboardID)
+	if !ok {
+		return nil, false
+	}
+	entry := value.(*leadersCacheEntry)
+	if time.Now().After(entry.expireAt) || len(entry.members) < pageSize {
+		return nil, false
+	}
+
+	members := make([]*Member, pageSize)
+	for i := range members {
+		member := entry.members[i]
+		members[i] = &member
+	}
+	return members, true
+}
@@ -0,0 +1,139 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	redis "github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Leaders Cache", func() {
+	var redisClient *extredis.Client
+	var leaderboards *Client
+	var lbID string
+
+	BeforeEach(func() {
+		var err error
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		leaderboards = NewClientWithRedis(redisClient)
+
+		lbID = uuid.NewV4().String()
+		for i := 0; i < 5; i++ {
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should serve the first page of leaders from the cache", func() {
+		err := leaderboards.WarmupLeaderboard(NewEmptyCtx(), lbID, 3, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+
+		// changes made directly in redis are not seen while the cache is valid
+		_, err = redisClient.Client.ZAdd(lbID, redis.Z{Score: 1000, Member: "member-new"}).Result()
+		Expect(err).NotTo(HaveOccurred())
+
+		members, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 3, 1, "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(HaveLen(3))
+		Expect(members[0].PublicID).To(Equal("member-0"))
+		Expect(members[0].Rank).To(Equal(1))
+		Expect(members[2].PublicID).To(Equal("member-2"))
+	})
+
+	It("should not serve pages larger than the cached members", func() {
+		err := leaderboards.WarmupLeaderboard(NewEmptyCtx(), lbID, 2, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+
+		members, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 5, 1, "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(HaveLen(5))
+	})
+
+	It("should not serve expired members", func() {
+		err := leaderboards.WarmupLeaderboard(NewEmptyCtx(), lbID, 3, time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = redisClient.Client.ZAdd(lbID, redis.Z{Score: 1000, Member: "member-new"}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		time.Sleep(5 * time.Millisecond)
+
+		members, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 3, 1, "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members[0].PublicID).To(Equal("member-new"))
+	})
+
+	It("should invalidate the cache on writes", func() {
+		err := leaderboards.WarmupLeaderboard(NewEmptyCtx(), lbID, 3, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-new", 1000, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		members, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 3, 1, "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members[0].PublicID).To(Equal("member-new"))
+	})
+
+	It("should invalidate the cache on rotation", func() {
+		err := leaderboards.WarmupLeaderboard(NewEmptyCtx(), lbID, 3, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = leaderboards.RotateLeaderboard(NewEmptyCtx(), lbID, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+
+		members, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 3, 1, "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(BeEmpty())
+	})
+
+	It("should keep the cache of other leaderboards when invalidating", func() {
+		otherID := uuid.NewV4().String()
+		_, err := leaderboards.SetMemberScore(NewEmptyCtx(), otherID, "member-0", 10, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		err = leaderboards.WarmupLeaderboard(NewEmptyCtx(), otherID, 1, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = redisClient.Client.ZAdd(otherID, redis.Z{Score: 1000, Member: "member-new"}).Result()
+		Expect(err).NotTo(HaveOccurred())
+
+		leaderboards.InvalidateCache(lbID)
+
+		members, err := leaderboards.GetLeaders(NewEmptyCtx(), otherID, 1, 1, "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members[0].PublicID).To(Equal("member-0"))
+	})
+
+	It("should invalidate the cache on demand", func() {
+		err := leaderboards.WarmupLeaderboard(NewEmptyCtx(), lbID, 3, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = redisClient.Client.ZAdd(lbID, redis.Z{Score: 1000, Member: "member-new"}).Result()
+		Expect(err).NotTo(HaveOccurred())
+
+		leaderboards.InvalidateCache(lbID)
+
+		members, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 3, 1, "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members[0].PublicID).To(Equal("member-new"))
+	})
+})
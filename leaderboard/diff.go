@@ -0,0 +1,145 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis"
+	"github.com/topfreegames/extensions/redis/interfaces"
+	"github.com/topfreegames/podium/log"
+	"go.uber.org/zap"
+)
+
+// diffBatchSize is how many members are compared in each pipeline by DiffLeaderboards
+const diffBatchSize = 100
+
+// ScoreChange is a member whose score is different in the two leaderboards compared by DiffLeaderboards
+type ScoreChange struct {
+	PublicID string `json:"publicID"`
+	ScoreA   int64  `json:"scoreA"`
+	ScoreB   int64  `json:"scoreB"`
+}
+
+// DiffReport describes how a leaderboard B differs from a leaderboard A
+type DiffReport struct {
+	Added   []string      `json:"added"`
+	Removed []string      `json:"removed"`
+	Changed []ScoreChange `json:"changed"`
+}
+
+// DiffLeaderboards compares two leaderboards, reporting the members only in B as added, the members only
+// in A as removed and the members in both with different scores as changed, all sorted by public ID.
+// Leaderboards are scanned in batches of 100 members whose scores in the other leaderboard are looked
+// up in a single pipeline, so neither leaderboard is ever loaded in full.
+func DiffLeaderboards(redisClient interfaces.RedisClient, idA, idB string, logger zap.Logger) (*DiffReport, error) {
+	report := &DiffReport{Added: []string{}, Removed: []string{}, Changed: []ScoreChange{}}
+
+	err := diffScan(redisClient, idA, idB, func(member *Member, cmd *redis.FloatCmd) {
+		if cmd.Err() == redis.Nil {
+			report.Removed = append(report.Removed, member.PublicID)
+		} else if int64(cmd.Val()) != member.Score {
+			report.Changed = append(report.Changed, ScoreChange{
+				PublicID: member.PublicID,
+				ScoreA:   member.Score,
+				ScoreB:   int64(cmd.Val()),
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = diffScan(redisClient, idB, idA, func(member *Member, cmd *redis.FloatCmd) {
+		if cmd.Err() == redis.Nil {
+			report.Added = append(report.Added, member.PublicID)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// ZSCAN may return the same member more than once
+	report.Added = uniqueSortedStrings(report.Added)
+	report.Removed = uniqueSortedStrings(report.Removed)
+	sort.Slice(report.Changed, func(i, j int) bool {
+		return report.Changed[i].PublicID < report.Changed[j].PublicID
+	})
+	changed := report.Changed[:0]
+	for i, change := range report.Changed {
+		if i == 0 || change.PublicID != report.Changed[i-1].PublicID {
+			changed = append(changed, change)
+		}
+	}
+	report.Changed = changed
+
+	log.D(logger, "Leaderboards compared.", func(cm log.CM) {
+		cm.Write(
+			zap.String("leaderboardA", idA),
+			zap.String("leaderboardB", idB),
+			zap.Int("added", len(report.Added)),
+			zap.Int("removed", len(report.Removed)),
+			zap.Int("changed", len(report.Changed)),
+		)
+	})
+	return report, nil
+}
+
+// diffScan scans the source leaderboard calling compare with each member and its score lookup in the
+// target leaderboard
+func diffScan(cli interfaces.RedisClient, sourceID, targetID string,
+	compare func(member *Member, cmd *redis.FloatCmd)) error {
+	var cursor uint64
+	for {
+		pipe := cli.TxPipeline()
+		scanCmd := pipe.ZScan(sourceID, cursor, "", diffBatchSize)
+		_, err := pipe.Exec()
+		if err != nil {
+			return fmt.Errorf("Scanning members of leaderboard %s failed: %v", sourceID, err)
+		}
+
+		values, nextCursor := scanCmd.Val()
+		if len(values) > 0 {
+			pipe = cli.TxPipeline()
+			members := make([]*Member, 0, len(values)/2)
+			scoreCmds := make([]*redis.FloatCmd, 0, len(values)/2)
+			for i := 0; i+1 < len(values); i += 2 {
+				score, _ := strconv.ParseFloat(values[i+1], 64)
+				members = append(members, &Member{PublicID: values[i], Score: int64(score)})
+				scoreCmds = append(scoreCmds, pipe.ZScore(targetID, values[i]))
+			}
+			_, err = pipe.Exec()
+			if err != nil && err != redis.Nil {
+				return fmt.Errorf("Retrieval of member scores in leaderboard %s failed: %v", targetID, err)
+			}
+			for i, member := range members {
+				compare(member, scoreCmds[i])
+			}
+		}
+
+		cursor = nextCursor
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+func uniqueSortedStrings(values []string) []string {
+	sort.Strings(values)
+	unique := values[:0]
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
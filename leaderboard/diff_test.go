@@ -0,0 +1,108 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+	. "github.com/topfreegames/podium/testing"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Leaderboard Diff", func() {
+	var redisClient *extredis.Client
+	var leaderboards *Client
+	var idA, idB string
+
+	BeforeEach(func() {
+		var err error
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		leaderboards = NewClientWithRedis(redisClient)
+
+		idA = uuid.NewV4().String()
+		idB = uuid.NewV4().String()
+	})
+
+	It("should report added, removed and changed members", func() {
+		err := leaderboards.SetMembersScore(NewEmptyCtx(), idA, Members{
+			&Member{PublicID: "member-1", Score: 10},
+			&Member{PublicID: "member-2", Score: 20},
+			&Member{PublicID: "member-3", Score: 30},
+		}, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		err = leaderboards.SetMembersScore(NewEmptyCtx(), idB, Members{
+			&Member{PublicID: "member-2", Score: 20},
+			&Member{PublicID: "member-3", Score: 35},
+			&Member{PublicID: "member-4", Score: 40},
+		}, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := DiffLeaderboards(redisClient.Client, idA, idB, NewMockLogger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(Equal(&DiffReport{
+			Added:   []string{"member-4"},
+			Removed: []string{"member-1"},
+			Changed: []ScoreChange{{PublicID: "member-3", ScoreA: 30, ScoreB: 35}},
+		}))
+	})
+
+	It("should compare leaderboards larger than a batch", func() {
+		membersA := make(Members, 250)
+		membersB := make(Members, 250)
+		for i := range membersA {
+			membersA[i] = &Member{PublicID: fmt.Sprintf("member-%03d", i), Score: int64(i)}
+			membersB[i] = &Member{PublicID: fmt.Sprintf("member-%03d", i+50), Score: int64(i + 50)}
+		}
+		membersB[0].Score = 1000
+		err := leaderboards.SetMembersScore(NewEmptyCtx(), idA, membersA, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		err = leaderboards.SetMembersScore(NewEmptyCtx(), idB, membersB, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := DiffLeaderboards(redisClient.Client, idA, idB, NewMockLogger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Added).To(HaveLen(50))
+		Expect(report.Added[0]).To(Equal("member-250"))
+		Expect(report.Removed).To(HaveLen(50))
+		Expect(report.Removed[49]).To(Equal("member-049"))
+		Expect(report.Changed).To(Equal([]ScoreChange{{PublicID: "member-050", ScoreA: 50, ScoreB: 1000}}))
+	})
+
+	It("should report no differences between identical leaderboards", func() {
+		_, err := leaderboards.SetMemberScore(NewEmptyCtx(), idA, "member-1", 10, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = leaderboards.SetMemberScore(NewEmptyCtx(), idB, "member-1", 10, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := DiffLeaderboards(redisClient.Client, idA, idB, NewMockLogger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(Equal(&DiffReport{Added: []string{}, Removed: []string{}, Changed: []ScoreChange{}}))
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		faultyClient := redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		_, err := DiffLeaderboards(faultyClient, idA, idB, NewMockLogger())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
@@ -0,0 +1,106 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// defaultEloRating is the rating members start with before playing their first match
+const defaultEloRating = 1200
+
+// WithDefaultEloRating sets the rating members start with before playing their first match
+func WithDefaultEloRating(rating int64) ClientOption {
+	return func(c *Client) {
+		c.eloRating = rating
+	}
+}
+
+// GetDefaultEloRating returns the rating members start with before playing their first match
+func (c *Client) GetDefaultEloRating() int64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.eloRating
+}
+
+// SetDefaultEloRating sets the rating members start with before playing their first match
+func (c *Client) SetDefaultEloRating(rating int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.eloRating = rating
+}
+
+// eloExpectedScore is the probability of a player rated rating beating one rated opponentRating
+func eloExpectedScore(rating, opponentRating int64) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponentRating-rating)/400))
+}
+
+// eloMaxAttempts is how many times UpdateEloRatings reads the ratings again when they change before the
+// new ones are written
+const eloMaxAttempts = 10
+
+// UpdateEloRatings applies the outcome of a match to the Elo ratings of the winner and the loser, using
+// their scores as ratings. Members not yet in the leaderboard start with the default Elo rating. Both
+// scores are updated atomically: the new ratings are only written if neither rating changed since they
+// were read, otherwise the ratings are read again, up to eloMaxAttempts times. The members are returned
+// with their new scores and ranks.
+func (c *Client) UpdateEloRatings(ctx context.Context, leaderboardID string, winnerID string, loserID string,
+	kFactor float64, scoreTTL string) (*Member, *Member, error) {
+	if winnerID == loserID {
+		return nil, nil, fmt.Errorf("Winner and loser must be different members")
+	}
+	if kFactor <= 0 {
+		return nil, nil, fmt.Errorf("K-factor must be positive")
+	}
+
+	for attempt := 0; attempt < eloMaxAttempts; attempt++ {
+		pipe := c.redisWithTracing(ctx).TxPipeline()
+		winnerCmd := pipe.ZScore(leaderboardID, winnerID)
+		loserCmd := pipe.ZScore(leaderboardID, loserID)
+		_, err := pipe.Exec()
+		if err != nil && err != redis.Nil {
+			return nil, nil, fmt.Errorf("Retrieval of Elo ratings failed: %v", err)
+		}
+
+		defaultRating := c.GetDefaultEloRating()
+		expectedScores := map[string]int64{}
+		ratingOf := func(memberID string, cmd *redis.FloatCmd) int64 {
+			if cmd.Err() != nil {
+				return defaultRating
+			}
+			expectedScores[memberID] = int64(cmd.Val())
+			return int64(cmd.Val())
+		}
+		winnerRating := ratingOf(winnerID, winnerCmd)
+		loserRating := ratingOf(loserID, loserCmd)
+
+		winner := &Member{PublicID: winnerID,
+			Score: int64(math.Floor(float64(winnerRating) + kFactor*(1-eloExpectedScore(winnerRating, loserRating)) + 0.5))}
+		loser := &Member{PublicID: loserID,
+			Score: int64(math.Floor(float64(loserRating) + kFactor*(0-eloExpectedScore(loserRating, winnerRating)) + 0.5))}
+
+		_, err = c.setMembersScore(ctx, leaderboardID, Members{winner, loser}, scoreTTL, false,
+			setScoreScriptOptions{expectedScores: expectedScores, abortOnConflict: true})
+		if redisErr := errors.Unwrap(err); redisErr != nil && strings.HasPrefix(redisErr.Error(), "CONFLICT ") {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return winner, loser, nil
+	}
+	return nil, nil, fmt.Errorf("Elo ratings kept changing after %d attempts", eloMaxAttempts)
+}
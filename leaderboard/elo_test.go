@@ -0,0 +1,123 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"math"
+	"sync"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Elo Ratings", func() {
+	var leaderboards *Client
+	var faultyLeaderboards *Client
+	var lbID string
+
+	BeforeEach(func() {
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		leaderboards = NewClientWithRedis(redisClient)
+
+		faultyRedisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		faultyRedisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		faultyLeaderboards = NewClientWithRedis(faultyRedisClient)
+
+		lbID = uuid.NewV4().String()
+	})
+
+	It("should start new members with the default rating", func() {
+		Expect(leaderboards.GetDefaultEloRating()).To(Equal(int64(1200)))
+
+		winner, loser, err := leaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "alice", "bob", 32, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(winner).To(Equal(&Member{PublicID: "alice", Score: 1216, Rank: 1}))
+		Expect(loser).To(Equal(&Member{PublicID: "bob", Score: 1184, Rank: 2}))
+	})
+
+	It("should update existing ratings using the expected scores", func() {
+		_, _, err := leaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "alice", "bob", 32, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		winner, loser, err := leaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "alice", "bob", 32, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(winner.Score).To(Equal(int64(1231)))
+		Expect(loser.Score).To(Equal(int64(1169)))
+
+		winner, loser, err = leaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "bob", "alice", 32, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(winner.PublicID).To(Equal("bob"))
+		Expect(winner.Score).To(BeNumerically(">", 1169+16))
+		Expect(loser.Score).To(BeNumerically("<", 1231-16))
+		Expect(winner.Score + loser.Score).To(Equal(int64(2400)))
+	})
+
+	It("should not lose concurrent updates of the same members", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, _, err := leaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "alice", "bob", 32, "")
+				Expect(err).NotTo(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+
+		alice, bob := 1200.0, 1200.0
+		for i := 0; i < 8; i++ {
+			expected := 1 / (1 + math.Pow(10, (bob-alice)/400))
+			alice, bob = math.Floor(alice+32*(1-expected)+0.5), math.Floor(bob-32*(1-expected)+0.5)
+		}
+		winner, err := leaderboards.GetMember(NewEmptyCtx(), lbID, "alice", "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(winner.Score).To(Equal(int64(alice)))
+		loser, err := leaderboards.GetMember(NewEmptyCtx(), lbID, "bob", "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loser.Score).To(Equal(int64(bob)))
+	})
+
+	It("should use the configured default rating", func() {
+		leaderboards.SetDefaultEloRating(1500)
+
+		winner, loser, err := leaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "alice", "bob", 20, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(winner.Score).To(Equal(int64(1510)))
+		Expect(loser.Score).To(Equal(int64(1490)))
+	})
+
+	It("should fail with invalid arguments", func() {
+		_, _, err := leaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "alice", "alice", 32, "")
+		Expect(err).To(HaveOccurred())
+
+		_, _, err = leaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "alice", "bob", 0, "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		_, _, err := faultyLeaderboards.UpdateEloRatings(NewEmptyCtx(), lbID, "alice", "bob", 32, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
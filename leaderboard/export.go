@@ -0,0 +1,178 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/go-redis/redis"
+	"github.com/topfreegames/extensions/redis/interfaces"
+)
+
+// exportBatchSize is the number of members scanned from redis at a time while exporting
+const exportBatchSize = 500
+
+// ExportFields are the member fields that can be exported, in their default order
+var ExportFields = []string{"publicID", "score", "rank", "expireAt"}
+
+// ExportLeaderboard writes the given fields of every member of the leaderboard to w, either as "csv",
+// with a header line, or as "jsonl", one JSON object per line. All fields are exported if none is given.
+// Members are scanned in batches and written in no particular order with their rank in descending order.
+// ZSCAN may return the same member more than once, so the IDs of the exported members are kept to skip
+// duplicates, which is the only memory usage that grows with the leaderboard size. It returns the number
+// of exported members.
+func (c *Client) ExportLeaderboard(ctx context.Context, leaderboardID string, fields []string, w io.Writer,
+	format string) (int64, error) {
+	if len(fields) == 0 {
+		fields = ExportFields
+	}
+	for _, field := range fields {
+		if !isExportField(field) {
+			return 0, fmt.Errorf("Invalid export field: %s", field)
+		}
+	}
+
+	var write func(member *Member) error
+	var flush func() error
+	switch format {
+	case "csv":
+		csvWriter := csv.NewWriter(w)
+		err := csvWriter.Write(fields)
+		if err != nil {
+			return 0, fmt.Errorf("Export of leaderboard failed: %v", err)
+		}
+		write = func(member *Member) error {
+			record := make([]string, len(fields))
+			for i, field := range fields {
+				record[i] = fmt.Sprint(exportFieldValue(member, field))
+			}
+			return csvWriter.Write(record)
+		}
+		flush = func() error {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		write = func(member *Member) error {
+			record := make(map[string]interface{}, len(fields))
+			for _, field := range fields {
+				record[field] = exportFieldValue(member, field)
+			}
+			return encoder.Encode(record)
+		}
+		flush = func() error { return nil }
+	default:
+		return 0, fmt.Errorf("Invalid export format: %s", format)
+	}
+
+	cli := c.redisWithTracing(ctx)
+	var exported int64
+	var cursor uint64
+	seen := map[string]bool{}
+	for {
+		members, nextCursor, err := scanExportBatch(cli, leaderboardID, cursor)
+		if err != nil {
+			return exported, err
+		}
+		for _, member := range members {
+			if seen[member.PublicID] {
+				continue
+			}
+			seen[member.PublicID] = true
+			err = write(member)
+			if err != nil {
+				return exported, fmt.Errorf("Export of leaderboard failed: %v", err)
+			}
+			exported++
+		}
+		err = flush()
+		if err != nil {
+			return exported, fmt.Errorf("Export of leaderboard failed: %v", err)
+		}
+
+		cursor = nextCursor
+		if cursor == 0 {
+			return exported, nil
+		}
+	}
+}
+
+func isExportField(field string) bool {
+	for _, exportField := range ExportFields {
+		if field == exportField {
+			return true
+		}
+	}
+	return false
+}
+
+func exportFieldValue(member *Member, field string) interface{} {
+	switch field {
+	case "publicID":
+		return member.PublicID
+	case "score":
+		return member.Score
+	case "rank":
+		return member.Rank
+	default:
+		return member.ExpireAt
+	}
+}
+
+func scanExportBatch(cli interfaces.RedisClient, leaderboardID string, cursor uint64) ([]*Member, uint64, error) {
+	pipe := cli.TxPipeline()
+	scanCmd := pipe.ZScan(leaderboardID, cursor, "", exportBatchSize)
+	_, err := pipe.Exec()
+	if err != nil {
+		return nil, 0, fmt.Errorf("Scanning members failed: %v", err)
+	}
+
+	values, nextCursor := scanCmd.Val()
+	members := make([]*Member, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		score, _ := strconv.ParseFloat(values[i+1], 64)
+		members = append(members, &Member{PublicID: values[i], Score: int64(score)})
+	}
+	if len(members) == 0 {
+		return members, nextCursor, nil
+	}
+
+	pipe = cli.TxPipeline()
+	rankCmds := make([]*redis.IntCmd, len(members))
+	ttlCmds := make([]*redis.FloatCmd, len(members))
+	for i, member := range members {
+		rankCmds[i] = pipe.ZRevRank(leaderboardID, member.PublicID)
+		ttlCmds[i] = pipe.ZScore(leaderboardID+":ttl", member.PublicID)
+	}
+	_, err = pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, 0, fmt.Errorf("Retrieval of members rank failed: %v", err)
+	}
+
+	ranked := members[:0]
+	for i, member := range members {
+		// members removed after being scanned are skipped
+		if rankCmds[i].Err() != nil {
+			continue
+		}
+		member.Rank = int(rankCmds[i].Val()) + 1
+		if ttlCmds[i].Err() == nil {
+			member.ExpireAt = int(ttlCmds[i].Val())
+		}
+		ranked = append(ranked, member)
+	}
+	return ranked, nextCursor, nil
+}
@@ -0,0 +1,108 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Leaderboard Export", func() {
+	var leaderboards *Client
+	var faultyLeaderboards *Client
+	var lbID string
+
+	BeforeEach(func() {
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		leaderboards = NewClientWithRedis(redisClient)
+
+		faultyRedisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		faultyRedisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		faultyLeaderboards = NewClientWithRedis(faultyRedisClient)
+
+		lbID = uuid.NewV4().String()
+		for i := 0; i < 1200; i++ {
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should export selected fields as csv", func() {
+		buffer := &bytes.Buffer{}
+		count, err := leaderboards.ExportLeaderboard(NewEmptyCtx(), lbID, []string{"publicID", "rank"}, buffer, "csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(int64(1200)))
+
+		records, err := csv.NewReader(buffer).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1201))
+		Expect(records[0]).To(Equal([]string{"publicID", "rank"}))
+		Expect(records[1:]).To(ContainElement([]string{"member-1199", "1"}))
+		Expect(records[1:]).To(ContainElement([]string{"member-0", "1200"}))
+	})
+
+	It("should export all fields as jsonl", func() {
+		buffer := &bytes.Buffer{}
+		count, err := leaderboards.ExportLeaderboard(NewEmptyCtx(), lbID, nil, buffer, "jsonl")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(int64(1200)))
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		Expect(lines).To(HaveLen(1200))
+		publicIDs := map[string]bool{}
+		for _, line := range lines {
+			record := map[string]interface{}{}
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			Expect(record).To(HaveLen(4))
+			Expect(record).To(HaveKey("expireAt"))
+			publicIDs[record["publicID"].(string)] = true
+			if record["publicID"] == "member-1199" {
+				Expect(record["score"]).To(BeEquivalentTo(1199))
+				Expect(record["rank"]).To(BeEquivalentTo(1))
+			}
+		}
+		Expect(publicIDs).To(HaveLen(1200))
+	})
+
+	It("should fail with invalid field or format", func() {
+		_, err := leaderboards.ExportLeaderboard(NewEmptyCtx(), lbID, []string{"name"}, &bytes.Buffer{}, "csv")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid export field"))
+
+		_, err = leaderboards.ExportLeaderboard(NewEmptyCtx(), lbID, nil, &bytes.Buffer{}, "xml")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid export format"))
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		_, err := faultyLeaderboards.ExportLeaderboard(NewEmptyCtx(), lbID, nil, &bytes.Buffer{}, "jsonl")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
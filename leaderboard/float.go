@@ -0,0 +1,239 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/topfreegames/extensions/redis/interfaces"
+)
+
+// MemberFloat is a member whose score keeps its fractional part
+type MemberFloat struct {
+	PublicID string  `json:"publicID"`
+	Score    float64 `json:"score"`
+	Rank     int     `json:"rank"`
+}
+
+// FloatLeaderboard wraps a Client reading and writing scores as float64, for leaderboards with fractional
+// scores such as Elo or decayed ratings. It only exposes the operations that read or write scores:
+// SetMemberScore, SetMembersScore, IncrementMemberScore, GetMember, GetMembers, GetRank, GetLeadersFloat,
+// GetAroundMe, TotalMembers, TotalPages, RemoveMember, RemoveMembers and RemoveLeaderboard. Other operations
+// must be called on the wrapped client and return scores truncated to integers. Scores are written directly
+// with ZADD and ZINCRBY, so score TTLs, hooks, validators and the other features of the score update
+// script, such as peaks, streaks and history, do not apply to them.
+type FloatLeaderboard struct {
+	client *Client
+}
+
+// NewFloatLeaderboard returns a FloatLeaderboard that wraps the given client
+func NewFloatLeaderboard(client *Client) *FloatLeaderboard {
+	return &FloatLeaderboard{client: client}
+}
+
+func (f *FloatLeaderboard) redisWithTracing(ctx context.Context) interfaces.RedisClient {
+	return f.client.redisWithTracing(ctx)
+}
+
+func (f *FloatLeaderboard) getMembersByRange(cli interfaces.RedisClient, leaderboardID string, startOffset int,
+	endOffset int, order string) ([]*MemberFloat, error) {
+	var values []redis.Z
+	var err error
+	if order == "desc" {
+		values, err = cli.ZRevRangeWithScores(leaderboardID, int64(startOffset), int64(endOffset)).Result()
+	} else {
+		values, err = cli.ZRangeWithScores(leaderboardID, int64(startOffset), int64(endOffset)).Result()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of members for range (start %d end %d) failed: %v", startOffset, endOffset, err)
+	}
+
+	members := make([]*MemberFloat, len(values))
+	for i, value := range values {
+		members[i] = &MemberFloat{PublicID: value.Member.(string), Score: value.Score, Rank: startOffset + i + 1}
+	}
+	return members, nil
+}
+
+// SetMemberScore sets the score of the member with the given ID
+func (f *FloatLeaderboard) SetMemberScore(ctx context.Context, leaderboardID string, memberID string,
+	score float64) (*MemberFloat, error) {
+	member := &MemberFloat{PublicID: memberID, Score: score}
+	err := f.SetMembersScore(ctx, leaderboardID, []*MemberFloat{member})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// SetMembersScore sets the scores of the members with the given IDs and fills their ranks
+func (f *FloatLeaderboard) SetMembersScore(ctx context.Context, leaderboardID string, members []*MemberFloat) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	f.client.InvalidateCache(leaderboardID)
+	pipe := f.redisWithTracing(ctx).TxPipeline()
+	values := make([]redis.Z, len(members))
+	for i, member := range members {
+		values[i] = redis.Z{Score: member.Score, Member: member.PublicID}
+	}
+	pipe.ZAdd(leaderboardID, values...)
+	rankCmds := make([]*redis.IntCmd, len(members))
+	for i, member := range members {
+		rankCmds[i] = pipe.ZRevRank(leaderboardID, member.PublicID)
+	}
+	_, err := pipe.Exec()
+	if err != nil {
+		return fmt.Errorf("Failed to update rank for members: %v", err)
+	}
+
+	for i, member := range members {
+		member.Rank = int(rankCmds[i].Val()) + 1
+	}
+	return nil
+}
+
+// IncrementMemberScore increments the score of the member with the given ID
+func (f *FloatLeaderboard) IncrementMemberScore(ctx context.Context, leaderboardID string, memberID string,
+	increment float64) (*MemberFloat, error) {
+	f.client.InvalidateCache(leaderboardID)
+	pipe := f.redisWithTracing(ctx).TxPipeline()
+	scoreCmd := pipe.ZIncrBy(leaderboardID, increment, memberID)
+	rankCmd := pipe.ZRevRank(leaderboardID, memberID)
+	_, err := pipe.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to increment score for member: %v", err)
+	}
+	return &MemberFloat{PublicID: memberID, Score: scoreCmd.Val(), Rank: int(rankCmd.Val()) + 1}, nil
+}
+
+// GetMember returns the score and the rank of the member with the given ID
+func (f *FloatLeaderboard) GetMember(ctx context.Context, leaderboardID string, memberID string,
+	order string) (*MemberFloat, error) {
+	members, err := f.GetMembers(ctx, leaderboardID, []string{memberID}, order)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, NewMemberNotFound(leaderboardID, memberID)
+	}
+	return members[0], nil
+}
+
+// GetMembers returns the score and the rank of the members with the given IDs, in the same order.
+// Members that are not in the leaderboard are skipped.
+func (f *FloatLeaderboard) GetMembers(ctx context.Context, leaderboardID string, memberIDs []string,
+	order string) ([]*MemberFloat, error) {
+	if len(memberIDs) == 0 {
+		return []*MemberFloat{}, nil
+	}
+
+	pipe := f.redisWithTracing(ctx).TxPipeline()
+	scoreCmds := make([]*redis.FloatCmd, len(memberIDs))
+	rankCmds := make([]*redis.IntCmd, len(memberIDs))
+	for i, memberID := range memberIDs {
+		scoreCmds[i] = pipe.ZScore(leaderboardID, memberID)
+		if order == "asc" {
+			rankCmds[i] = pipe.ZRank(leaderboardID, memberID)
+		} else {
+			rankCmds[i] = pipe.ZRevRank(leaderboardID, memberID)
+		}
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Could not get members: %v", err)
+	}
+
+	members := make([]*MemberFloat, 0, len(memberIDs))
+	for i, memberID := range memberIDs {
+		if scoreCmds[i].Err() != nil || rankCmds[i].Err() != nil {
+			continue
+		}
+		members = append(members, &MemberFloat{
+			PublicID: memberID,
+			Score:    scoreCmds[i].Val(),
+			Rank:     int(rankCmds[i].Val()) + 1,
+		})
+	}
+	return members, nil
+}
+
+// GetRank returns the rank of the member with the given ID
+func (f *FloatLeaderboard) GetRank(ctx context.Context, leaderboardID string, memberID string, order string) (int, error) {
+	return f.client.GetRank(ctx, leaderboardID, memberID, order)
+}
+
+// GetLeadersFloat returns a page of members with their fractional scores
+func (f *FloatLeaderboard) GetLeadersFloat(ctx context.Context, leaderboardID string, pageSize, page int,
+	order string) ([]*MemberFloat, error) {
+	if page < 1 {
+		page = 1
+	}
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	startOffset := (page - 1) * pageSize
+	return f.getMembersByRange(f.redisWithTracing(ctx), leaderboardID, startOffset, startOffset+pageSize-1, order)
+}
+
+// GetAroundMe returns a page of members with the member with the given ID in the middle
+func (f *FloatLeaderboard) GetAroundMe(ctx context.Context, leaderboardID string, pageSize int, memberID string,
+	order string) ([]*MemberFloat, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	member, err := f.GetMember(ctx, leaderboardID, memberID, order)
+	if err != nil {
+		return nil, err
+	}
+	totalMembers, err := f.TotalMembers(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	startOffset := member.Rank - 1 - pageSize/2
+	if startOffset+pageSize > totalMembers {
+		startOffset = totalMembers - pageSize
+	}
+	if startOffset < 0 {
+		startOffset = 0
+	}
+	return f.getMembersByRange(f.redisWithTracing(ctx), leaderboardID, startOffset, startOffset+pageSize-1, order)
+}
+
+// TotalMembers returns the total number of members in the leaderboard
+func (f *FloatLeaderboard) TotalMembers(ctx context.Context, leaderboardID string) (int, error) {
+	return f.client.TotalMembers(ctx, leaderboardID)
+}
+
+// TotalPages returns the number of pages of the leaderboard
+func (f *FloatLeaderboard) TotalPages(ctx context.Context, leaderboardID string, pageSize int) (int, error) {
+	return f.client.TotalPages(ctx, leaderboardID, pageSize)
+}
+
+// RemoveMember removes the member with the given ID from the leaderboard
+func (f *FloatLeaderboard) RemoveMember(ctx context.Context, leaderboardID string, memberID string) error {
+	return f.client.RemoveMember(ctx, leaderboardID, memberID)
+}
+
+// RemoveMembers removes the members with the given IDs from the leaderboard
+func (f *FloatLeaderboard) RemoveMembers(ctx context.Context, leaderboardID string, memberIDs []interface{}) error {
+	return f.client.RemoveMembers(ctx, leaderboardID, memberIDs)
+}
+
+// RemoveLeaderboard removes the leaderboard
+func (f *FloatLeaderboard) RemoveLeaderboard(ctx context.Context, leaderboardID string) error {
+	return f.client.RemoveLeaderboard(ctx, leaderboardID)
+}
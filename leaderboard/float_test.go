@@ -0,0 +1,99 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Float Leaderboard", func() {
+	var floatLeaderboards *FloatLeaderboard
+	var faultyFloatLeaderboards *FloatLeaderboard
+	var lbID string
+
+	BeforeEach(func() {
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		floatLeaderboards = NewFloatLeaderboard(NewClientWithRedis(redisClient))
+
+		faultyRedisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		faultyRedisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		faultyFloatLeaderboards = NewFloatLeaderboard(NewClientWithRedis(faultyRedisClient))
+
+		lbID = uuid.NewV4().String()
+		for i := 0; i < 10; i++ {
+			_, err := floatLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), float64(i)+0.5)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should keep fractional scores", func() {
+		member, err := floatLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-new", 4.75)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(member).To(Equal(&MemberFloat{PublicID: "member-new", Score: 4.75, Rank: 6}))
+
+		member, err = floatLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-new", 0.125)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(member.Score).To(Equal(4.875))
+
+		member, err = floatLeaderboards.GetMember(NewEmptyCtx(), lbID, "member-new", "asc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(member).To(Equal(&MemberFloat{PublicID: "member-new", Score: 4.875, Rank: 6}))
+	})
+
+	It("should return pages of members with fractional scores", func() {
+		members, err := floatLeaderboards.GetLeadersFloat(NewEmptyCtx(), lbID, 3, 2, "desc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(Equal([]*MemberFloat{
+			{PublicID: "member-6", Score: 6.5, Rank: 4},
+			{PublicID: "member-5", Score: 5.5, Rank: 5},
+			{PublicID: "member-4", Score: 4.5, Rank: 6},
+		}))
+
+		members, err = floatLeaderboards.GetAroundMe(NewEmptyCtx(), lbID, 3, "member-0", "desc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(HaveLen(3))
+		Expect(members[2]).To(Equal(&MemberFloat{PublicID: "member-0", Score: 0.5, Rank: 10}))
+	})
+
+	It("should skip members not in the leaderboard", func() {
+		members, err := floatLeaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-1", "invalid-member"}, "desc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(Equal([]*MemberFloat{{PublicID: "member-1", Score: 1.5, Rank: 9}}))
+
+		_, err = floatLeaderboards.GetMember(NewEmptyCtx(), lbID, "invalid-member", "desc")
+		Expect(err).To(Equal(NewMemberNotFound(lbID, "invalid-member")))
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		_, err := faultyFloatLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 1.5)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+		_, err = faultyFloatLeaderboards.GetLeadersFloat(NewEmptyCtx(), lbID, 3, 1, "desc")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
@@ -0,0 +1,95 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+// defaultHookBufferSize is how many score events may wait for each hook before new ones are dropped
+const defaultHookBufferSize = 100
+
+// LeaderboardHook is notified of member score changes made through a Client
+type LeaderboardHook interface {
+	OnScoreSet(leaderboardID string, member *Member)
+}
+
+type hookEvent struct {
+	leaderboardID string
+	member        Member
+}
+
+// hookWorker delivers score events to a single hook from its own goroutine until done is closed
+type hookWorker struct {
+	hook   LeaderboardHook
+	events chan *hookEvent
+	done   chan struct{}
+}
+
+func (w *hookWorker) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event := <-w.events:
+			member := event.member
+			w.hook.OnScoreSet(event.leaderboardID, &member)
+		}
+	}
+}
+
+// WithHookBufferSize sets how many score events may wait for each hook before new ones are dropped
+func WithHookBufferSize(size int) ClientOption {
+	return func(c *Client) {
+		c.hookBufferSize = size
+	}
+}
+
+// RegisterHook adds a hook notified after every successful score update. Each hook is called from its
+// own goroutine, in the order of the updates, so hooks never slow down score updates or each other.
+// If a hook falls behind by more events than the buffer size, new events for it are dropped.
+// UnregisterAllHooks stops the goroutines.
+func (c *Client) RegisterHook(hook LeaderboardHook) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	bufferSize := c.hookBufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultHookBufferSize
+	}
+	worker := &hookWorker{
+		hook:   hook,
+		events: make(chan *hookEvent, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go worker.run()
+	c.hooks = append(c.hooks, worker)
+}
+
+// UnregisterAllHooks removes all hooks and stops their goroutines, events not yet dispatched are dropped
+func (c *Client) UnregisterAllHooks() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, worker := range c.hooks {
+		close(worker.done)
+	}
+	c.hooks = nil
+}
+
+// notifyHooks queues a score event for each of the registered hooks, if any
+func (c *Client) notifyHooks(leaderboardID string, members Members) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, worker := range c.hooks {
+		for _, member := range members {
+			select {
+			case worker.events <- &hookEvent{leaderboardID: leaderboardID, member: *member}:
+			default:
+			}
+		}
+	}
+}
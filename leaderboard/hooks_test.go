@@ -0,0 +1,128 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+type recordingHook struct {
+	mutex   sync.Mutex
+	members []*Member
+}
+
+func (h *recordingHook) OnScoreSet(leaderboardID string, member *Member) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.members = append(h.members, member)
+}
+
+func (h *recordingHook) recorded() []*Member {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.members
+}
+
+type blockingHook struct {
+	blocked chan struct{}
+}
+
+func (h *blockingHook) OnScoreSet(leaderboardID string, member *Member) {
+	<-h.blocked
+}
+
+var _ = Describe("Leaderboard Hooks", func() {
+	var leaderboards *Client
+
+	BeforeEach(func() {
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		leaderboards = NewClientWithRedis(redisClient, WithHookBufferSize(10))
+	})
+
+	AfterEach(func() {
+		leaderboards.UnregisterAllHooks()
+	})
+
+	It("should notify hooks of score updates", func() {
+		lbID := uuid.NewV4().String()
+		hook := &recordingHook{}
+		otherHook := &recordingHook{}
+		leaderboards.RegisterHook(hook)
+		leaderboards.RegisterHook(otherHook)
+
+		_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = leaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 50, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(hook.recorded).Should(HaveLen(2))
+		Expect(hook.recorded()[0].PublicID).To(Equal("member-1"))
+		Expect(hook.recorded()[0].Score).To(Equal(int64(100)))
+		Expect(hook.recorded()[1].Score).To(Equal(int64(150)))
+		Expect(hook.recorded()[1].Rank).To(Equal(1))
+		Eventually(otherHook.recorded).Should(HaveLen(2))
+	})
+
+	It("should not let a slow hook delay the others", func() {
+		lbID := uuid.NewV4().String()
+		blocked := make(chan struct{})
+		defer close(blocked)
+		leaderboards.RegisterHook(&blockingHook{blocked: blocked})
+		hook := &recordingHook{}
+		leaderboards.RegisterHook(hook)
+
+		for i := 0; i < 3; i++ {
+			_, err := leaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 10, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Eventually(hook.recorded).Should(HaveLen(3))
+	})
+
+	It("should stop hook goroutines when hooks are unregistered", func() {
+		before := runtime.NumGoroutine()
+		for i := 0; i < 5; i++ {
+			leaderboards.RegisterHook(&recordingHook{})
+		}
+		Expect(runtime.NumGoroutine()).To(BeNumerically(">=", before+5))
+
+		leaderboards.UnregisterAllHooks()
+
+		Eventually(runtime.NumGoroutine).Should(BeNumerically("<=", before))
+	})
+
+	It("should not notify hooks after they are unregistered", func() {
+		lbID := uuid.NewV4().String()
+		hook := &recordingHook{}
+		leaderboards.RegisterHook(hook)
+		leaderboards.UnregisterAllHooks()
+
+		_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Consistently(hook.recorded).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,152 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/go-redis/redis"
+	"github.com/topfreegames/extensions/redis/interfaces"
+	"github.com/topfreegames/podium/log"
+	"go.uber.org/zap"
+)
+
+// MembersIterator walks through all members of a leaderboard in batches using ZSCAN, so the whole
+// leaderboard never needs to be loaded in memory. Members are returned in no particular order, each
+// one with its rank in the given order. It is safe for concurrent use.
+type MembersIterator struct {
+	redisClient   interfaces.RedisClient
+	leaderboardID string
+	batchSize     int
+	order         string
+	logger        zap.Logger
+
+	mutex   sync.Mutex
+	cursor  uint64
+	done    bool
+	pending []*Member
+}
+
+// NewMembersIterator returns an iterator over the members of the given leaderboard
+func NewMembersIterator(redisClient interfaces.RedisClient, leaderboardID string, batchSize int, order string,
+	logger zap.Logger) *MembersIterator {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	return &MembersIterator{
+		redisClient:   redisClient,
+		leaderboardID: leaderboardID,
+		batchSize:     batchSize,
+		order:         order,
+		logger:        logger.With(zap.String("source", "leaderboard"), zap.String("operation", "membersIterator")),
+	}
+}
+
+// GetMembersIterator returns an iterator over the members of the given leaderboard
+func (c *Client) GetMembersIterator(ctx context.Context, leaderboardID string, batchSize int, order string,
+	logger zap.Logger) *MembersIterator {
+	return NewMembersIterator(c.redisWithTracing(ctx), leaderboardID, batchSize, order, logger)
+}
+
+// Next returns up to batchSize members and whether there may be more members to be returned
+func (it *MembersIterator) Next() ([]*Member, bool, error) {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	for len(it.pending) < it.batchSize && !it.done {
+		err := it.scan()
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	size := it.batchSize
+	if len(it.pending) < size {
+		size = len(it.pending)
+	}
+	batch := it.pending[:size]
+	it.pending = it.pending[size:]
+
+	members, err := it.rank(batch)
+	if err != nil {
+		return nil, false, err
+	}
+
+	log.D(it.logger, "Members batch retrieved.", func(cm log.CM) {
+		cm.Write(zap.String("leaderboardID", it.leaderboardID), zap.Int("members", len(members)))
+	})
+	return members, len(it.pending) > 0 || !it.done, nil
+}
+
+// Reset makes the iterator start again from the beginning of the leaderboard
+func (it *MembersIterator) Reset() {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+
+	it.cursor = 0
+	it.done = false
+	it.pending = nil
+}
+
+func (it *MembersIterator) scan() error {
+	pipe := it.redisClient.TxPipeline()
+	cmd := pipe.ZScan(it.leaderboardID, it.cursor, "", int64(it.batchSize))
+	_, err := pipe.Exec()
+	if err != nil {
+		return fmt.Errorf("Scanning members failed: %v", err)
+	}
+
+	values, cursor := cmd.Val()
+	for i := 0; i+1 < len(values); i += 2 {
+		score, _ := strconv.ParseFloat(values[i+1], 64)
+		it.pending = append(it.pending, &Member{PublicID: values[i], Score: int64(score)})
+	}
+	it.cursor = cursor
+	it.done = cursor == 0
+	return nil
+}
+
+func (it *MembersIterator) rank(batch []*Member) ([]*Member, error) {
+	if len(batch) == 0 {
+		return batch, nil
+	}
+
+	pipe := it.redisClient.TxPipeline()
+	cmds := make([]*redis.IntCmd, len(batch))
+	for i, member := range batch {
+		if it.order == "desc" {
+			cmds[i] = pipe.ZRevRank(it.leaderboardID, member.PublicID)
+		} else {
+			cmds[i] = pipe.ZRank(it.leaderboardID, member.PublicID)
+		}
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of members rank failed: %v", err)
+	}
+
+	members := make([]*Member, 0, len(batch))
+	for i, member := range batch {
+		// members removed after being scanned are skipped
+		if cmds[i].Err() != nil {
+			continue
+		}
+		member.Rank = int(cmds[i].Val()) + 1
+		members = append(members, member)
+	}
+	return members, nil
+}
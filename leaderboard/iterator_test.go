@@ -0,0 +1,95 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+	. "github.com/topfreegames/podium/testing"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Members Iterator", func() {
+	var redisClient *extredis.Client
+	var leaderboards *Client
+
+	BeforeEach(func() {
+		var err error
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		leaderboards = NewClientWithRedis(redisClient)
+	})
+
+	It("should iterate through all members in batches", func() {
+		lbID := uuid.NewV4().String()
+		for i := 0; i < 250; i++ {
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(1000-i), false, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		it := NewMembersIterator(redisClient.Client, lbID, 100, "desc", NewMockLogger())
+		seen := map[string]*Member{}
+		for {
+			members, hasMore, err := it.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(members)).To(BeNumerically("<=", 100))
+			for _, member := range members {
+				seen[member.PublicID] = member
+			}
+			if !hasMore {
+				break
+			}
+		}
+
+		Expect(seen).To(HaveLen(250))
+		Expect(seen["member-0"].Rank).To(Equal(1))
+		Expect(seen["member-0"].Score).To(Equal(int64(1000)))
+		Expect(seen["member-249"].Rank).To(Equal(250))
+	})
+
+	It("should start again after reset", func() {
+		lbID := uuid.NewV4().String()
+		for i := 0; i < 10; i++ {
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		it := NewMembersIterator(redisClient.Client, lbID, 10, "asc", NewMockLogger())
+		members, hasMore, err := it.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(HaveLen(10))
+		Expect(hasMore).To(BeFalse())
+
+		it.Reset()
+		members, _, err = it.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(HaveLen(10))
+	})
+
+	It("should return no members for empty leaderboard", func() {
+		it := NewMembersIterator(redisClient.Client, uuid.NewV4().String(), 10, "desc", NewMockLogger())
+		members, hasMore, err := it.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(members).To(BeEmpty())
+		Expect(hasMore).To(BeFalse())
+	})
+})
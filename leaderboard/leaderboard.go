@@ -11,16 +11,24 @@ package leaderboard
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/url"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
 	"github.com/spf13/viper"
 	"github.com/topfreegames/extensions/redis/interfaces"
 	"github.com/topfreegames/podium/util"
@@ -73,12 +81,114 @@ func (slice Members) Swap(i, j int) {
 // Client represents the leaderboard manager object. Capable of managing multiple leaderboards.
 type Client struct {
 	redisClient *tfgredis.Client
+
+	mutex           sync.RWMutex
+	defaultScoreTTL time.Duration
+	heatmapEnabled  bool
+	scoreValidator  ScoreValidator
+	peakScore       bool
+	scoreHistory    bool
+	streak          bool
+	changeLog       bool
+	topScoreEver    bool
+	updatesWindow   time.Duration
+	joinTracking    bool
+	cardinality     bool
+	hooks           []*hookWorker
+	hookBufferSize  int
+	eloRating       int64
+	asyncWorkers    int
+	asyncBufferSize int
+	asyncUpdates    chan *asyncUpdate
+	asyncPending    int64
+}
+
+// ClientOption configures optional behaviour of a Client
+type ClientOption func(*Client)
+
+// WithDefaultScoreTTL sets the score TTL used when no TTL is given when setting member scores
+func WithDefaultScoreTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.defaultScoreTTL = ttl
+	}
+}
+
+// WithPeakScore enables keeping the highest score of each member, see GetMemberPeakScore
+func WithPeakScore() ClientOption {
+	return func(c *Client) {
+		c.peakScore = true
+	}
+}
+
+// WithScoreHistory enables keeping the score history of each member, see GetMemberScoreAt
+func WithScoreHistory() ClientOption {
+	return func(c *Client) {
+		c.scoreHistory = true
+	}
+}
+
+// WithStreaks enables counting consecutive score improvements of each member, see
+// GetMembersWithHighestStreak
+func WithStreaks() ClientOption {
+	return func(c *Client) {
+		c.streak = true
+	}
+}
+
+// WithChangeLog enables appending every score change to the leaderboard change log, see GetChangeLogs
+func WithChangeLog() ClientOption {
+	return func(c *Client) {
+		c.changeLog = true
+	}
+}
+
+// WithTopScoreEver enables keeping the highest score ever reached in each leaderboard, see GetTopScoreEver
+func WithTopScoreEver() ClientOption {
+	return func(c *Client) {
+		c.topScoreEver = true
+	}
+}
+
+// WithUpdateTracking enables keeping the members updated within the last window, see GetMembersChangedSince
+// and GetMembersUpdatedInWindow. Older updates are dropped on every write.
+func WithUpdateTracking(window time.Duration) ClientOption {
+	return func(c *Client) {
+		c.updatesWindow = window
+	}
+}
+
+// WithJoinTracking enables recording when each member first joins a leaderboard, see GetGrowthRate
+func WithJoinTracking() ClientOption {
+	return func(c *Client) {
+		c.joinTracking = true
+	}
+}
+
+// WithScoreCardinality enables keeping a HyperLogLog of the scores written to each leaderboard, used by the
+// approximate GetScoreCardinality
+func WithScoreCardinality() ClientOption {
+	return func(c *Client) {
+		c.cardinality = true
+	}
+}
+
+// WithHeatmap enables counting score updates by hour of the day, see GetLeaderboardHeatmap
+func WithHeatmap() ClientOption {
+	return func(c *Client) {
+		c.heatmapEnabled = true
+	}
 }
 
 func (c *Client) redisWithTracing(ctx context.Context) interfaces.RedisClient {
 	return c.redisClient.Trace(ctx)
 }
 
+// changeLogOperations are the operations recorded in the change log for each set score script operation
+var changeLogOperations = map[string]string{
+	"ZADD":    "set",
+	"ZINCRBY": "increment",
+}
+
 func getSetScoreScript(operation string) *redis.Script {
 	return redis.NewScript(fmt.Sprintf(`
 		-- Script params:
@@ -88,10 +198,54 @@ func getSetScoreScript(operation string) *redis.Script {
 		-- ARGV[3] defines if the previous rank should be returned
 		-- ARGV[4] defines the ttl of the player score
 		-- ARGV[5] defines the current unix timestamp
+		-- ARGV[6] defines if members not in the leaderboard should be skipped
+		-- ARGV[7] defines if the update should be counted in the heatmap
+		-- ARGV[8] defines the current hour of the day
+		-- ARGV[9] defines if the peak score of each member should be kept
+		-- ARGV[10] defines if the score history of each member should be kept
+		-- ARGV[11] defines the maximum number of score history entries of each member
+		-- ARGV[12] defines if the score improvement streak of each member should be kept
+		-- ARGV[13] defines if members should be skipped unless their new score is higher than the current one
+		-- ARGV[14] defines if score changes should be appended to the change log
+		-- ARGV[15] defines if the all-time top score of the leaderboard should be kept
+		-- ARGV[16] defines for how many seconds member updates are kept, 0 if they are not kept
+		-- ARGV[17] defines if the time each member joined the leaderboard should be kept
+		-- ARGV[18] defines if members should be skipped unless their current score is their expectedScore,
+		-- members without an expectedScore must not be in the leaderboard
+		-- ARGV[19] defines if the whole update should be aborted, instead of skipping the member, when a
+		-- current score is not the expected one
+		-- ARGV[20] defines if the new scores should be added to the score cardinality HyperLogLog
+		-- ARGV[21] defines the approximate maximum number of change log entries
+
+		-- auxiliary keys expire along with the leaderboard
+		local expire_with_leaderboard = function(key)
+			if ARGV[2] ~= "-1" and redis.call("TTL", key) == -1 then
+				redis.call("EXPIREAT", key, ARGV[2])
+			end
+		end
+
+		-- skips members that are not in the leaderboard, whose score would not increase or whose current score
+		-- is not the expected one if required. Conflicts abort the update before anything is written.
+		local members = {}
+		for i,mem in ipairs(cjson.decode(ARGV[1])) do
+			local current = nil
+			if ARGV[6] == "1" or ARGV[13] == "1" or ARGV[18] == "1" then
+				current = tonumber(redis.call("ZSCORE", KEYS[1], mem["publicID"]))
+			end
+			if ARGV[18] == "1" and ARGV[19] == "1" and current ~= mem["expectedScore"] then
+				return redis.error_reply("CONFLICT "..mem["publicID"])
+			end
+			if (ARGV[6] ~= "1" or current) and (ARGV[13] ~= "1" or not current or tonumber(mem["score"]) > current) and
+				(ARGV[18] ~= "1" or current == mem["expectedScore"]) then
+				table.insert(members, mem)
+			end
+		end
+		if #members == 0 then
+			return {}
+		end
 
 		-- creates leaderboard or just sets score of member
 		local key_pairs = {}
-		local members = cjson.decode(ARGV[1])
 		local score_ttl = ARGV[4]
 		if score_ttl == nil or score_ttl == "" then
 			score_ttl = "inf"
@@ -102,8 +256,121 @@ func getSetScoreScript(operation string) *redis.Script {
 			if (ARGV[3] == "1") then
 				mem["previousRank"] = tonumber(redis.call("ZREVRANK", KEYS[1], mem["publicID"])) or -2
 			end
+			if ARGV[12] == "1" or ARGV[14] == "1" then
+				mem["previousScore"] = tonumber(redis.call("ZSCORE", KEYS[1], mem["publicID"]))
+			end
+		end
+		if "%s" == "ZINCRBY" then
+			for i = 1, #key_pairs, 2 do
+				redis.call("ZINCRBY", KEYS[1], key_pairs[i], key_pairs[i + 1])
+			end
+		else
+			redis.call("ZADD", KEYS[1], unpack(key_pairs))
+		end
+
+		-- keeps the all-time top score of the leaderboard
+		if ARGV[15] == "1" then
+			local alltime_key = KEYS[1]..":alltime"
+			local record = redis.call("ZREVRANGE", alltime_key, 0, 0, "WITHSCORES")
+			local record_score = tonumber(record[2])
+			for i,mem in ipairs(members) do
+				local score = tonumber(redis.call("ZSCORE", KEYS[1], mem["publicID"]))
+				if record_score == nil or score > record_score then
+					redis.call("ZADD", alltime_key, score, mem["publicID"])
+					record_score = score
+				end
+			end
+			expire_with_leaderboard(alltime_key)
+		end
+
+		-- keeps the time each member joined and was last updated
+		local updates = {}
+		local last_updated = {}
+		for i,mem in ipairs(members) do
+			table.insert(updates, ARGV[5])
+			table.insert(updates, mem["publicID"])
+			table.insert(last_updated, mem["publicID"])
+			table.insert(last_updated, ARGV[5])
+		end
+		if tonumber(ARGV[16]) > 0 then
+			local updates_key = KEYS[1]..":updates"
+			redis.call("ZADD", updates_key, unpack(updates))
+			redis.call("ZREMRANGEBYSCORE", updates_key, "-inf", "("..(tonumber(ARGV[5]) - tonumber(ARGV[16])))
+			expire_with_leaderboard(updates_key)
+		end
+		if ARGV[17] == "1" then
+			redis.call("ZADD", KEYS[1]..":joined", "NX", unpack(updates))
+			expire_with_leaderboard(KEYS[1]..":joined")
+		end
+		redis.call("HMSET", KEYS[1]..":lastupdated", unpack(last_updated))
+		expire_with_leaderboard(KEYS[1]..":lastupdated")
+
+		-- counts the distinct scores written
+		if ARGV[20] == "1" then
+			local scores = {}
+			for i,mem in ipairs(members) do
+				table.insert(scores, redis.call("ZSCORE", KEYS[1], mem["publicID"]))
+			end
+			redis.call("PFADD", KEYS[1]..":cardinality", unpack(scores))
+			expire_with_leaderboard(KEYS[1]..":cardinality")
+		end
+
+		-- counts updates by hour of the day
+		if ARGV[7] == "1" then
+			redis.call("INCRBY", KEYS[1]..":heatmap:"..ARGV[8], #members)
+			expire_with_leaderboard(KEYS[1]..":heatmap:"..ARGV[8])
+		end
+
+		-- keeps the highest score of each member
+		if ARGV[9] == "1" then
+			for i,mem in ipairs(members) do
+				local score = tonumber(redis.call("ZSCORE", KEYS[1], mem["publicID"]))
+				local peak = tonumber(redis.call("HGET", KEYS[1]..":peak", mem["publicID"]))
+				if peak == nil or score > peak then
+					redis.call("HSET", KEYS[1]..":peak", mem["publicID"], score)
+				end
+			end
+			expire_with_leaderboard(KEYS[1]..":peak")
+		end
+
+		-- keeps the score history of each member, oldest first
+		if ARGV[10] == "1" then
+			for i,mem in ipairs(members) do
+				local history_key = KEYS[1]..":history:"..mem["publicID"]
+				local score = tonumber(redis.call("ZSCORE", KEYS[1], mem["publicID"]))
+				redis.call("RPUSH", history_key, cjson.encode({timestamp=tonumber(ARGV[5]), score=score}))
+				redis.call("LTRIM", history_key, -tonumber(ARGV[11]), -1)
+				expire_with_leaderboard(history_key)
+			end
+		end
+
+		-- counts consecutive score improvements of each member
+		if ARGV[12] == "1" then
+			for i,mem in ipairs(members) do
+				local score = tonumber(redis.call("ZSCORE", KEYS[1], mem["publicID"]))
+				if mem["previousScore"] ~= nil and score > mem["previousScore"] then
+					redis.call("HINCRBY", KEYS[1]..":streak", mem["publicID"], 1)
+				else
+					redis.call("HSET", KEYS[1]..":streak", mem["publicID"], 0)
+				end
+			end
+			expire_with_leaderboard(KEYS[1]..":streak")
+		end
+
+		-- appends every score change to the change log stream
+		if ARGV[14] == "1" then
+			for i,mem in ipairs(members) do
+				local score = tonumber(redis.call("ZSCORE", KEYS[1], mem["publicID"]))
+				local old_score = ""
+				if mem["previousScore"] ~= nil then
+					old_score = string.format("%%.0f", mem["previousScore"])
+				end
+				redis.call("XADD", KEYS[1]..":changelog", "MAXLEN", "~", ARGV[21], "*", "timestamp", ARGV[5],
+					"memberID", mem["publicID"], "oldScore", old_score, "newScore", string.format("%%.0f", score),
+					"operation", "%s")
+			end
+			expire_with_leaderboard(KEYS[1]..":changelog")
 		end
-		redis.call("%s", KEYS[1], unpack(key_pairs))
 
 		-- If expiration is required set expiration
 		if (ARGV[2] ~= "-1") then
@@ -143,7 +410,46 @@ func getSetScoreScript(operation string) *redis.Script {
 			table.insert(result, expire_at)
 		end
 		return result
-	`, operation, operation))
+	`, operation, changeLogOperations[operation], operation))
+}
+
+// setScoreScriptOptions are the options of a set score script run that do not come from the client settings.
+// If expectedScores is not nil members are only updated if their current score is the one in expectedScores,
+// or if they are not in the leaderboard and have no expected score. If abortOnConflict is also set a single
+// mismatch fails the whole update with a "CONFLICT <memberID>" error and nothing is written.
+type setScoreScriptOptions struct {
+	prevRank        bool
+	mustExist       bool
+	onlyHigher      bool
+	expectedScores  map[string]int64
+	abortOnConflict bool
+}
+
+// scriptMember is a member as passed to the set score script
+type scriptMember struct {
+	*Member
+	ExpectedScore *int64 `json:"expectedScore,omitempty"`
+}
+
+// runSetScoreScript sets the scores of the members, or increments them if operation is ZINCRBY, with the set
+// score script, passing the client settings along with the given options
+func (c *Client) runSetScoreScript(ctx context.Context, operation string, leaderboardID string, members Members,
+	expireAt int64, scoreTTL string, opts setScoreScriptOptions) (interface{}, error) {
+	scriptMembers := make([]scriptMember, len(members))
+	for i, member := range members {
+		scriptMembers[i] = scriptMember{Member: member}
+		if expected, ok := opts.expectedScores[member.PublicID]; ok {
+			scriptMembers[i].ExpectedScore = &expected
+		}
+	}
+	jsonMembers, _ := json.Marshal(scriptMembers)
+	now := time.Now()
+	return getSetScoreScript(operation).Run(c.redisWithTracing(ctx), []string{leaderboardID}, jsonMembers, expireAt,
+		opts.prevRank, scoreTTL, now.Unix(), opts.mustExist, c.HeatmapEnabled(), now.UTC().Hour(),
+		c.PeakScoreEnabled(), c.ScoreHistoryEnabled(), scoreHistoryMaxEntries, c.StreakEnabled(), opts.onlyHigher,
+		c.ChangeLogEnabled(), c.TopScoreEverEnabled(), int64(c.UpdateTrackingWindow()/time.Second),
+		c.JoinTrackingEnabled(), opts.expectedScores != nil, opts.abortOnConflict,
+		c.ScoreCardinalityEnabled(), changeLogMaxEntries).Result()
 }
 
 //getMembersByRange for a given leaderboard
@@ -158,7 +464,7 @@ func getMembersByRange(redisClient interfaces.RedisClient, leaderboard string, s
 		values, err = cli.ZRangeWithScores(leaderboard, int64(startOffset), int64(endOffset)).Result()
 	}
 	if err != nil {
-		return nil, fmt.Errorf("Retrieval of members for range (start %d end %d) failed: %v", startOffset, endOffset, err)
+		return nil, fmt.Errorf("Retrieval of members for range (start %d end %d) failed: %w", startOffset, endOffset, err)
 	}
 	members := make([]*Member, len(values))
 	for i := 0; i < len(members); i++ {
@@ -183,7 +489,7 @@ func getMemberIDWithClosestScore(redisClient interfaces.RedisClient, leaderboard
 	values, err := cli.ZRevRangeByScore(leaderboard, redis.ZRangeBy{Min: "-inf", Max: strconv.FormatInt(score, 10), Offset: 0, Count: 1}).Result()
 
 	if err != nil {
-		return "", fmt.Errorf("Retrieval of member with closest score to %d failed: %v", score, err)
+		return "", fmt.Errorf("Retrieval of member with closest score to %d failed: %w", score, err)
 	}
 
 	if len(values) < 1 {
@@ -194,7 +500,8 @@ func getMemberIDWithClosestScore(redisClient interfaces.RedisClient, leaderboard
 }
 
 // NewClient creates a leaderboard prepared to receive commands (host, port, password, db and connectionTimeout are used for connecting to Redis)
-func NewClient(host string, port int, password string, db int, connectionTimeout int) (*Client, error) {
+func NewClient(host string, port int, password string, db int, connectionTimeout int,
+	opts ...ClientOption) (*Client, error) {
 	redisURL := url.URL{
 		Scheme: "redis",
 		User:   url.UserPassword("", password),
@@ -211,20 +518,115 @@ func NewClient(host string, port int, password string, db int, connectionTimeout
 		return nil, err
 	}
 
-	return &Client{redisClient: cli}, nil
+	return NewClientWithRedis(cli, opts...), nil
 }
 
 //NewClientWithRedis creates a leaderboard using an already connected tfg Redis
-func NewClientWithRedis(cli *tfgredis.Client) *Client {
-	return &Client{redisClient: cli}
+func NewClientWithRedis(cli *tfgredis.Client, opts ...ClientOption) *Client {
+	c := &Client{redisClient: cli, eloRating: defaultEloRating}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetDefaultScoreTTL returns the score TTL used when no TTL is given, 0 if scores do not expire by default
+func (c *Client) GetDefaultScoreTTL() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.defaultScoreTTL
+}
+
+// SetDefaultScoreTTL sets the score TTL used when no TTL is given when setting member scores
+func (c *Client) SetDefaultScoreTTL(ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.defaultScoreTTL = ttl
+}
+
+// ClearDefaultScoreTTL makes scores set without a TTL never expire
+func (c *Client) ClearDefaultScoreTTL() {
+	c.SetDefaultScoreTTL(0)
+}
+
+// scoreTTLOrDefault returns the given score TTL, or the default one if it is empty or infinite
+func (c *Client) scoreTTLOrDefault(scoreTTL string) string {
+	if scoreTTL != "" && scoreTTL != "inf" {
+		return scoreTTL
+	}
+	if defaultScoreTTL := c.GetDefaultScoreTTL(); defaultScoreTTL > 0 {
+		return strconv.FormatInt(int64(defaultScoreTTL/time.Second), 10)
+	}
+	return scoreTTL
+}
+
+// ScoreValidator checks whether a member score may change from currentScore to proposedScore,
+// returning an error to reject the change. Members not in the leaderboard have a current score of 0.
+type ScoreValidator func(memberID string, currentScore, proposedScore int64) error
+
+// MaxIncrementValidator returns a ScoreValidator that rejects score increases greater than maxDelta
+func MaxIncrementValidator(maxDelta int64) ScoreValidator {
+	return func(memberID string, currentScore, proposedScore int64) error {
+		if proposedScore-currentScore > maxDelta {
+			return fmt.Errorf("Score increase of %d for member %s exceeds the maximum of %d",
+				proposedScore-currentScore, memberID, maxDelta)
+		}
+		return nil
+	}
+}
+
+// WithScoreValidator sets a validator called before every score update, see SetScoreValidator
+func WithScoreValidator(validator ScoreValidator) ClientOption {
+	return func(c *Client) {
+		c.scoreValidator = validator
+	}
+}
+
+// SetScoreValidator sets a validator called before every score update. Updates the validator rejects
+// are aborted and its error is returned. A nil validator disables validation.
+func (c *Client) SetScoreValidator(validator ScoreValidator) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.scoreValidator = validator
+}
+
+// validateScores calls the score validator, if any, for each of the given members. If increment is
+// true member scores are increments to the current scores instead of the new scores.
+func (c *Client) validateScores(ctx context.Context, leaderboardID string, members Members, increment bool) error {
+	c.mutex.RLock()
+	validator := c.scoreValidator
+	c.mutex.RUnlock()
+	if validator == nil {
+		return nil
+	}
+
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	scoreCmds := make([]*redis.FloatCmd, len(members))
+	for i, member := range members {
+		scoreCmds[i] = pipe.ZScore(leaderboardID, member.PublicID)
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("Retrieval of current scores failed: %w", err)
+	}
+
+	for i, member := range members {
+		currentScore := int64(scoreCmds[i].Val())
+		proposedScore := member.Score
+		if increment {
+			proposedScore += currentScore
+		}
+		if err := validator(member.PublicID, currentScore, proposedScore); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // IncrementMemberScore sets the score to the member with the given ID
 func (c *Client) IncrementMemberScore(ctx context.Context, leaderboardID string, memberID string, increment int,
 	scoreTTL string) (*Member, error) {
 
-	script := getSetScoreScript("ZINCRBY")
-
 	expireAt, err := util.GetExpireAt(leaderboardID)
 	if err != nil {
 		if _, ok := err.(*util.LeaderboardExpiredError); ok {
@@ -234,11 +636,18 @@ func (c *Client) IncrementMemberScore(ctx context.Context, leaderboardID string,
 		}
 	}
 
-	jsonMembers, _ := json.Marshal(Members{&Member{PublicID: memberID, Score: int64(increment)}})
+	members := Members{&Member{PublicID: memberID, Score: int64(increment)}}
+	if err := c.validateScores(ctx, leaderboardID, members, true); err != nil {
+		return nil, err
+	}
+
+	c.InvalidateCache(leaderboardID)
+	scoreTTL = c.scoreTTLOrDefault(scoreTTL)
 	// TODO use prevRank instead of hard coded false
-	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, jsonMembers, expireAt, false, scoreTTL, time.Now().Unix()).Result()
+	result, err := c.runSetScoreScript(ctx, "ZINCRBY", leaderboardID, members, expireAt, scoreTTL,
+		setScoreScriptOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("Could not increment score for member: %v", err)
+		return nil, fmt.Errorf("Could not increment score for member: %w", err)
 	}
 	rank := int(result.([]interface{})[1].(int64)) + 1
 	score := result.([]interface{})[2].(int64)
@@ -247,6 +656,7 @@ func (c *Client) IncrementMemberScore(ctx context.Context, leaderboardID string,
 	if scoreTTL != "" && scoreTTL != "inf" {
 		nMember.ExpireAt = int(result.([]interface{})[4].(int64))
 	}
+	c.notifyHooks(leaderboardID, Members{&nMember})
 	return &nMember, err
 }
 
@@ -260,44 +670,195 @@ func (c *Client) SetMemberScore(ctx context.Context, leaderboardID string, membe
 // SetMembersScore sets the scores of the members with the given IDs
 func (c *Client) SetMembersScore(ctx context.Context, leaderboardID string, members Members, prevRank bool,
 	scoreTTL string) error {
+	_, err := c.setMembersScore(ctx, leaderboardID, members, scoreTTL, false, setScoreScriptOptions{prevRank: prevRank})
+	return err
+}
+
+// PartialUpdateResult lists the members updated by a conditional update and the IDs of the skipped ones
+type PartialUpdateResult struct {
+	Updated Members
+	Skipped []string
+}
+
+// SetMembersScoreConditional sets the scores of the members with the given IDs. If mustExist is true members
+// that are not in the leaderboard are skipped instead of created, and are listed in the result.
+func (c *Client) SetMembersScoreConditional(ctx context.Context, leaderboardID string, members Members, prevRank bool,
+	scoreTTL string, mustExist bool) (*PartialUpdateResult, error) {
+	updated, err := c.setMembersScore(ctx, leaderboardID, members, scoreTTL, false,
+		setScoreScriptOptions{prevRank: prevRank, mustExist: mustExist})
+	if err != nil {
+		return nil, err
+	}
+	return newPartialUpdateResult(members, updated), nil
+}
+
+// SetMembersScoreIfHigher sets the scores of the members with the given IDs, skipping members whose
+// current score is equal or higher than the new one. Skipped members are listed in the result.
+func (c *Client) SetMembersScoreIfHigher(ctx context.Context, leaderboardID string, members Members, prevRank bool,
+	scoreTTL string) (*PartialUpdateResult, error) {
+	updated, err := c.setMembersScore(ctx, leaderboardID, members, scoreTTL, false,
+		setScoreScriptOptions{prevRank: prevRank, onlyHigher: true})
+	if err != nil {
+		return nil, err
+	}
+	return newPartialUpdateResult(members, updated), nil
+}
+
+func newPartialUpdateResult(members Members, updated Members) *PartialUpdateResult {
+	result := &PartialUpdateResult{Updated: updated, Skipped: []string{}}
+	isUpdated := make(map[*Member]bool, len(updated))
+	for _, member := range updated {
+		isUpdated[member] = true
+	}
+	for _, member := range members {
+		if !isUpdated[member] {
+			result.Skipped = append(result.Skipped, member.PublicID)
+		}
+	}
+	return result
+}
+
+// setMembersScore sets the scores of the members with the given IDs, or increments them by the member
+// scores if increment is true, and returns the members that were updated. Members may be skipped depending
+// on the options, see setScoreScriptOptions.
+func (c *Client) setMembersScore(ctx context.Context, leaderboardID string, members Members, scoreTTL string,
+	increment bool, opts setScoreScriptOptions) (Members, error) {
 
 	expireAt, err := util.GetExpireAt(leaderboardID)
 	if err != nil {
 		if _, ok := err.(*util.LeaderboardExpiredError); ok {
-			return err
+			return nil, err
 		} else {
-			return fmt.Errorf("Could not get expiration: %v", err)
+			return nil, fmt.Errorf("Could not get expiration: %v", err)
 		}
 	}
 
-	script := getSetScoreScript("ZADD")
+	if err := c.validateScores(ctx, leaderboardID, members, increment); err != nil {
+		return nil, err
+	}
+	return c.writeMembersScore(ctx, leaderboardID, members, expireAt, scoreTTL, increment, opts)
+}
 
-	jsonMembers, _ := json.Marshal(members)
-	newRanks, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, jsonMembers, expireAt, prevRank,
-		scoreTTL, time.Now().Unix()).Result()
+// writeMembersScore runs the set score script for already validated members, fills them with their new
+// state and notifies the hooks, returning the members that were updated
+func (c *Client) writeMembersScore(ctx context.Context, leaderboardID string, members Members, expireAt int64,
+	scoreTTL string, increment bool, opts setScoreScriptOptions) (Members, error) {
+	c.InvalidateCache(leaderboardID)
+	scoreTTL = c.scoreTTLOrDefault(scoreTTL)
+	operation := "ZADD"
+	if increment {
+		operation = "ZINCRBY"
+	}
+	newRanks, err := c.runSetScoreScript(ctx, operation, leaderboardID, members, expireAt, scoreTTL, opts)
 	if err != nil {
-		return fmt.Errorf("Failed to update rank for members: %v", err)
+		return nil, fmt.Errorf("Failed to update rank for members: %w", err)
 	}
 
+	// skipped members are not returned by the script, the remaining ones keep their order
 	res := newRanks.([]interface{})
+	updated := Members{}
+	memberIndex := 0
 	for i := 0; i < len(res); i += 5 {
-		memberIndex := i / 5
-		members[memberIndex].PublicID = res[i].(string)
-		members[memberIndex].Score = res[i+2].(int64)
-		members[memberIndex].Rank = int(res[i+1].(int64)) + 1
-		members[memberIndex].PreviousRank = int(res[i+3].(int64)) + 1
+		for members[memberIndex].PublicID != res[i].(string) {
+			memberIndex++
+		}
+		member := members[memberIndex]
+		member.Score = res[i+2].(int64)
+		member.Rank = int(res[i+1].(int64)) + 1
+		member.PreviousRank = int(res[i+3].(int64)) + 1
 		if scoreTTL != "" && scoreTTL != "inf" {
-			members[memberIndex].ExpireAt = int(res[i+4].(int64))
+			member.ExpireAt = int(res[i+4].(int64))
+		}
+		updated = append(updated, member)
+		memberIndex++
+	}
+
+	c.notifyHooks(leaderboardID, updated)
+	return updated, nil
+}
+
+// TransactionError indicates SetMembersScoreTransactional did not update any member because the update of
+// the member with the given ID failed
+type TransactionError struct {
+	LeaderboardID string
+	MemberID      string
+	Err           error
+}
+
+func (e *TransactionError) Error() string {
+	return fmt.Sprintf("Transaction on leaderboard %s failed at member %s: %v", e.LeaderboardID, e.MemberID, e.Err)
+}
+
+// ErrScoreChanged is the cause of a TransactionError when a member score changed after it was validated
+var ErrScoreChanged = fmt.Errorf("Member score changed during the transaction")
+
+// SetMembersScoreTransactional sets the scores of the members with the given IDs, updating either all of them
+// or none. The current scores are read in a single round trip and checked with the score validator, then a
+// single script checks that none of them changed in between before writing any member. Validation failures
+// and concurrent changes are reported as a TransactionError. Redis does not roll back scripts, so a redis
+// command failing halfway through the write, such as on an auxiliary key of the wrong type, is returned as is
+// and may leave the batch partially applied.
+func (c *Client) SetMembersScoreTransactional(ctx context.Context, leaderboardID string, members Members,
+	prevRank bool, scoreTTL string) error {
+	expireAt, err := util.GetExpireAt(leaderboardID)
+	if err != nil {
+		if _, ok := err.(*util.LeaderboardExpiredError); ok {
+			return err
+		}
+		return fmt.Errorf("Could not get expiration: %v", err)
+	}
+
+	seen := make(map[string]bool, len(members))
+	for _, member := range members {
+		if member.PublicID == "" {
+			return &TransactionError{LeaderboardID: leaderboardID, Err: fmt.Errorf("Member ID must not be empty")}
+		}
+		if seen[member.PublicID] {
+			return &TransactionError{LeaderboardID: leaderboardID, MemberID: member.PublicID,
+				Err: fmt.Errorf("Member is updated more than once")}
+		}
+		seen[member.PublicID] = true
+	}
+
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	scoreCmds := make([]*redis.FloatCmd, len(members))
+	for i, member := range members {
+		scoreCmds[i] = pipe.ZScore(leaderboardID, member.PublicID)
+	}
+	_, err = pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("Retrieval of current scores failed: %v", err)
+	}
+
+	c.mutex.RLock()
+	validator := c.scoreValidator
+	c.mutex.RUnlock()
+	currentScores := make(map[string]int64, len(members))
+	for i, member := range members {
+		if scoreCmds[i].Err() == nil {
+			currentScores[member.PublicID] = int64(scoreCmds[i].Val())
+		}
+		if validator == nil {
+			continue
+		}
+		if err := validator(member.PublicID, currentScores[member.PublicID], member.Score); err != nil {
+			return &TransactionError{LeaderboardID: leaderboardID, MemberID: member.PublicID, Err: err}
 		}
 	}
 
+	_, err = c.writeMembersScore(ctx, leaderboardID, members, expireAt, scoreTTL, false,
+		setScoreScriptOptions{prevRank: prevRank, expectedScores: currentScores, abortOnConflict: true})
+	if redisErr := errors.Unwrap(err); redisErr != nil && strings.HasPrefix(redisErr.Error(), "CONFLICT ") {
+		return &TransactionError{LeaderboardID: leaderboardID,
+			MemberID: strings.TrimPrefix(redisErr.Error(), "CONFLICT "), Err: ErrScoreChanged}
+	}
 	return err
 }
 
 func (c *Client) totalMembers(r interfaces.RedisClient, leaderboardID string) (int, error) {
 	total, err := r.ZCard(leaderboardID).Result()
 	if err != nil {
-		return 0, fmt.Errorf("Retrieval of total members failed: %v", err)
+		return 0, fmt.Errorf("Retrieval of total members failed: %w", err)
 	}
 	return int(total), nil
 }
@@ -307,20 +868,55 @@ func (c *Client) TotalMembers(ctx context.Context, leaderboardID string) (int, e
 	return c.totalMembers(c.redisWithTracing(ctx), leaderboardID)
 }
 
+// memberDataHashes are the suffixes of the auxiliary hashes keyed by member ID whose fields are deleted
+// along with the members, mapped to the prefixes of the member IDs in their fields
+var memberDataHashes = map[string][]string{
+	"lastupdated": {""},
+	"peak":        {""},
+	"prevrank":    {"desc:", "asc:"},
+	"streak":      {""},
+}
+
+// memberDataSets are the suffixes of the auxiliary sorted sets of member IDs whose members are deleted along
+// with the members
+var memberDataSets = []string{"ttl", "updates", "joined"}
+
+// removeMembers removes the members with the given publicIDs from the leaderboard, the member data sets and
+// the member data hashes
+func (c *Client) removeMembers(ctx context.Context, leaderboardID string, memberIDs []interface{}) error {
+	c.InvalidateCache(leaderboardID)
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	pipe.ZRem(leaderboardID, memberIDs...)
+	for _, suffix := range memberDataSets {
+		pipe.ZRem(fmt.Sprintf("%s:%s", leaderboardID, suffix), memberIDs...)
+	}
+	for suffix, prefixes := range memberDataHashes {
+		fields := []string{}
+		for _, prefix := range prefixes {
+			for _, memberID := range memberIDs {
+				fields = append(fields, fmt.Sprintf("%s%v", prefix, memberID))
+			}
+		}
+		pipe.HDel(fmt.Sprintf("%s:%s", leaderboardID, suffix), fields...)
+	}
+	_, err := pipe.Exec()
+	return err
+}
+
 // RemoveMembers removes the members with the given publicIDs from the leaderboard
 func (c *Client) RemoveMembers(ctx context.Context, leaderboardID string, memberIDs []interface{}) error {
-	_, err := c.redisWithTracing(ctx).ZRem(leaderboardID, memberIDs...).Result()
+	err := c.removeMembers(ctx, leaderboardID, memberIDs)
 	if err != nil {
-		return fmt.Errorf("Members removal failed: %v", err)
+		return fmt.Errorf("Members removal failed: %w", err)
 	}
 	return nil
 }
 
 // RemoveMember removes the member with the given publicID from the leaderboard
 func (c *Client) RemoveMember(ctx context.Context, leaderboardID string, memberID string) error {
-	_, err := c.redisWithTracing(ctx).ZRem(leaderboardID, memberID).Result()
+	err := c.removeMembers(ctx, leaderboardID, []interface{}{memberID})
 	if err != nil {
-		return fmt.Errorf("Member removal failed: %v", err)
+		return fmt.Errorf("Member removal failed: %w", err)
 	}
 	return nil
 }
@@ -330,7 +926,7 @@ func (c *Client) totalPages(redisClient interfaces.RedisClient, leaderboardID st
 	pages := 0
 	total, err := redisClient.ZCard(leaderboardID).Result()
 	if err != nil {
-		return 0, fmt.Errorf("Number of pages could not be retrieved: %v", err)
+		return 0, fmt.Errorf("Number of pages could not be retrieved: %w", err)
 	}
 	pages = int(math.Ceil(float64(total) / float64(pageSize)))
 	return pages, nil
@@ -369,7 +965,7 @@ func (c *Client) getMember(r interfaces.RedisClient, leaderboardID string, membe
 
 	result, err := script.Run(r, []string{leaderboardID, memberID}, strconv.FormatBool(includeTTL)).Result()
 	if err != nil {
-		return nil, fmt.Errorf("Getting member information failed: %v", err)
+		return nil, fmt.Errorf("Getting member information failed: %w", err)
 	}
 
 	res := result.([]interface{})
@@ -396,8 +992,10 @@ func (c *Client) GetMember(ctx context.Context, leaderboardID string, memberID s
 	return c.getMember(c.redisWithTracing(ctx), leaderboardID, memberID, order, includeTTL)
 }
 
-// GetMembers returns the score and the rank of the members with the given IDs
-func (c *Client) GetMembers(ctx context.Context, leaderboardID string, memberIDs []string, order string, includeTTL bool) ([]*Member, error) {
+// GetMembers returns the score and the rank of the members with the given IDs.
+// If includePreviousRank is true PreviousRank is filled as documented in fillPreviousRanks.
+func (c *Client) GetMembers(ctx context.Context, leaderboardID string, memberIDs []string, order string, includeTTL bool,
+	includePreviousRank bool) ([]*Member, error) {
 
 	var operations = map[string]string{
 		"rank_desc": "ZREVRANK",
@@ -435,7 +1033,7 @@ func (c *Client) GetMembers(ctx context.Context, leaderboardID string, memberIDs
 
 	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, strings.Join(memberIDs, ","), strconv.FormatBool(includeTTL)).Result()
 	if err != nil {
-		return nil, fmt.Errorf("Getting members information failed: %v", err)
+		return nil, fmt.Errorf("Getting members information failed: %w", err)
 	}
 
 	res := result.([]interface{})
@@ -464,6 +1062,12 @@ func (c *Client) GetMembers(ctx context.Context, leaderboardID string, memberIDs
 	}
 
 	sort.Sort(members)
+	if includePreviousRank {
+		err = c.fillPreviousRanks(c.redisWithTracing(ctx), leaderboardID, members, order)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return members, nil
 }
 
@@ -489,7 +1093,18 @@ func (c *Client) getAroundMe(redisClient interfaces.RedisClient, leaderboardID s
 		currentMember = &Member{PublicID: memberID, Score: 0, Rank: totalMembers + 1}
 	}
 
-	startOffset := currentMember.Rank - (pageSize / 2)
+	startOffset, endOffset := aroundMeOffsets(currentMember.Rank, totalMembers, pageSize)
+	members, err := getMembersByRange(redisClient, leaderboardID, startOffset, endOffset, order)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve information around a specific member: %w", err)
+	}
+
+	return members, nil
+}
+
+// aroundMeOffsets returns the range of the page centered in the member with the given rank
+func aroundMeOffsets(rank, totalMembers, pageSize int) (int, int) {
+	startOffset := rank - (pageSize / 2)
 	if startOffset < 0 {
 		startOffset = 0
 	}
@@ -501,13 +1116,7 @@ func (c *Client) getAroundMe(redisClient interfaces.RedisClient, leaderboardID s
 			startOffset = 0
 		}
 	}
-
-	members, err := getMembersByRange(redisClient, leaderboardID, startOffset, endOffset, order)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to retrieve information around a specific member: %v", err)
-	}
-
-	return members, nil
+	return startOffset, endOffset
 }
 
 // GetAroundMe returns a page of results centered in the member with the given ID
@@ -522,12 +1131,77 @@ func (c *Client) GetAroundScore(ctx context.Context, leaderboardID string, pageS
 	redisClient := c.redisWithTracing(ctx)
 	memberID, err := getMemberIDWithClosestScore(redisClient, leaderboardID, score)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to retrieve information around a specific score (%d): %v", score, err)
+		return nil, fmt.Errorf("Failed to retrieve information around a specific score (%d): %w", score, err)
 	}
 
 	return c.getAroundMe(redisClient, leaderboardID, pageSize, memberID, order, true)
 }
 
+// GetAroundMeMultiLeaderboard returns, for each of the given leaderboards, a page of results centered in the
+// member with the given ID, as returned by GetAroundMe. Leaderboards the member is not in are not included.
+// The ranks of the member in all leaderboards are fetched in a single pipeline, and so are the pages.
+func (c *Client) GetAroundMeMultiLeaderboard(ctx context.Context, memberID string, leaderboardIDs []string,
+	pageSize int, order string) (map[string][]*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	redisClient := c.redisWithTracing(ctx)
+
+	pipe := redisClient.TxPipeline()
+	rankCmds := make([]*redis.IntCmd, len(leaderboardIDs))
+	totalCmds := make([]*redis.IntCmd, len(leaderboardIDs))
+	for i, leaderboardID := range leaderboardIDs {
+		if order == "desc" {
+			rankCmds[i] = pipe.ZRevRank(leaderboardID, memberID)
+		} else {
+			rankCmds[i] = pipe.ZRank(leaderboardID, memberID)
+		}
+		totalCmds[i] = pipe.ZCard(leaderboardID)
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of member ranks failed: %v", err)
+	}
+
+	pipe = redisClient.TxPipeline()
+	startOffsets := make(map[string]int, len(leaderboardIDs))
+	pageCmds := make(map[string]*redis.ZSliceCmd, len(leaderboardIDs))
+	for i, leaderboardID := range leaderboardIDs {
+		if rankCmds[i].Err() != nil {
+			continue
+		}
+		rank := int(rankCmds[i].Val()) + 1
+		startOffset, endOffset := aroundMeOffsets(rank, int(totalCmds[i].Val()), pageSize)
+		startOffsets[leaderboardID] = startOffset
+		if order == "desc" {
+			pageCmds[leaderboardID] = pipe.ZRevRangeWithScores(leaderboardID, int64(startOffset), int64(endOffset))
+		} else {
+			pageCmds[leaderboardID] = pipe.ZRangeWithScores(leaderboardID, int64(startOffset), int64(endOffset))
+		}
+	}
+	if len(pageCmds) > 0 {
+		_, err = pipe.Exec()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve information around a specific member: %v", err)
+		}
+	}
+
+	result := make(map[string][]*Member, len(pageCmds))
+	for leaderboardID, cmd := range pageCmds {
+		values := cmd.Val()
+		members := make([]*Member, len(values))
+		for i, value := range values {
+			members[i] = &Member{
+				PublicID: value.Member.(string),
+				Score:    int64(value.Score),
+				Rank:     startOffsets[leaderboardID] + i + 1,
+			}
+		}
+		result[leaderboardID] = members
+	}
+	return result, nil
+}
+
 // GetRank returns the rank of the member with the given ID
 func (c *Client) GetRank(ctx context.Context, leaderboardID string, memberID string, order string) (int, error) {
 	var rank int64
@@ -542,18 +1216,26 @@ func (c *Client) GetRank(ctx context.Context, leaderboardID string, memberID str
 			return -1, NewMemberNotFound(leaderboardID, memberID)
 		}
 
-		return -1, fmt.Errorf("Failed to retrieve rank of specific member: %v", err)
+		return -1, fmt.Errorf("Failed to retrieve rank of specific member: %w", err)
 	}
 	return int(rank + 1), nil
 }
 
-// GetLeaders returns a page of members with rank and score
-func (c *Client) GetLeaders(ctx context.Context, leaderboardID string, pageSize, page int, order string) ([]*Member, error) {
+// GetLeaders returns a page of members with rank and score.
+// If includePreviousRank is true PreviousRank is filled as documented in fillPreviousRanks.
+func (c *Client) GetLeaders(ctx context.Context, leaderboardID string, pageSize, page int, order string,
+	includePreviousRank bool) ([]*Member, error) {
 	redisClient := c.redisWithTracing(ctx)
 	if page < 1 {
 		page = 1
 	}
 
+	if page == 1 && order == "desc" && !includePreviousRank {
+		if members, ok := cachedLeaders(leaderboardID, pageSize); ok {
+			return members, nil
+		}
+	}
+
 	totalPages, err := c.totalPages(redisClient, leaderboardID, pageSize)
 	if err != nil {
 		return nil, err
@@ -566,30 +1248,90 @@ func (c *Client) GetLeaders(ctx context.Context, leaderboardID string, pageSize,
 	redisIndex := page - 1
 	startOffset := redisIndex * pageSize
 	endOffset := (startOffset + pageSize) - 1
-	return getMembersByRange(redisClient, leaderboardID, startOffset, endOffset, order)
-}
-
-//GetTopPercentage of members in the leaderboard.
-func (c *Client) GetTopPercentage(ctx context.Context, leaderboardID string, pageSize, amount, maxMembers int, order string) ([]*Member, error) {
-	if amount < 1 || amount > 100 {
-		return nil, fmt.Errorf("Percentage must be a valid integer between 1 and 100.")
+	members, err := getMembersByRange(redisClient, leaderboardID, startOffset, endOffset, order)
+	if err != nil {
+		return nil, err
 	}
 
-	if order != "desc" && order != "asc" {
-		order = "desc"
+	if includePreviousRank {
+		err = c.fillPreviousRanks(redisClient, leaderboardID, members, order)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return members, nil
+}
 
-	var operations = map[string]string{
-		"range_desc": "ZREVRANGE",
-		"rank_desc":  "ZREVRANK",
-		"range_asc":  "ZRANGE",
-		"rank_asc":   "ZRANK",
+// fillPreviousRanks sets the PreviousRank of the given members to the rank they had the last time they were
+// read with previous ranks included, and stores their current rank in the <leaderboard>:prevrank hash to be
+// used as the previous rank of the next read. Snapshots are kept per order and are only taken by reads that
+// include previous ranks, so the previous rank is the rank seen by the last of such reads, not the rank before
+// the last score update. Members that were never read this way get a PreviousRank of -1. The snapshot expires
+// with the leaderboard and removed members are deleted from it.
+func (c *Client) fillPreviousRanks(redisClient interfaces.RedisClient, leaderboardID string, members []*Member,
+	order string) error {
+	if len(members) == 0 {
+		return nil
 	}
 
 	script := redis.NewScript(`
 		-- Script params:
 		-- KEYS[1] is the name of the leaderboard
-		-- ARGV[1] is the desired percentage (0.0 to 1.0)
+		-- ARGV[1] is the order of the ranks
+		-- ARGV[2..n] are pairs of member's public ID and current rank
+
+		local snapshot_key = KEYS[1]..":prevrank"
+		local previous_ranks = {}
+		for i = 2, #ARGV, 2 do
+			local field = ARGV[1] .. ":" .. ARGV[i]
+			table.insert(previous_ranks, tonumber(redis.call("HGET", snapshot_key, field)) or -1)
+			redis.call("HSET", snapshot_key, field, ARGV[i + 1])
+		end
+
+		-- the snapshot expires along with the leaderboard
+		local ttl = redis.call("PTTL", KEYS[1])
+		if ttl > 0 then
+			redis.call("PEXPIRE", snapshot_key, ttl)
+		end
+		return previous_ranks
+	`)
+
+	args := []interface{}{order}
+	for _, member := range members {
+		args = append(args, member.PublicID, member.Rank)
+	}
+	result, err := script.Run(redisClient, []string{leaderboardID}, args...).Result()
+	if err != nil {
+		return fmt.Errorf("Getting members previous rank failed: %w", err)
+	}
+
+	for i, previousRank := range result.([]interface{}) {
+		members[i].PreviousRank = int(previousRank.(int64))
+	}
+	return nil
+}
+
+//GetTopPercentage of members in the leaderboard.
+func (c *Client) GetTopPercentage(ctx context.Context, leaderboardID string, pageSize, amount, maxMembers int, order string) ([]*Member, error) {
+	if amount < 1 || amount > 100 {
+		return nil, fmt.Errorf("Percentage must be a valid integer between 1 and 100.")
+	}
+
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	var operations = map[string]string{
+		"range_desc": "ZREVRANGE",
+		"rank_desc":  "ZREVRANK",
+		"range_asc":  "ZRANGE",
+		"rank_asc":   "ZRANK",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the desired percentage (0.0 to 1.0)
 		-- ARGV[2] is the maximum number of members returned
 
 		local totalNumber = redis.call("ZCARD", KEYS[1])
@@ -645,14 +1387,4297 @@ func (c *Client) GetTopPercentage(ctx context.Context, leaderboardID string, pag
 
 // RemoveLeaderboard removes a leaderboard from redis
 func (c *Client) RemoveLeaderboard(ctx context.Context, leaderboardID string) error {
+	c.InvalidateCache(leaderboardID)
 	_, err := c.redisWithTracing(ctx).Del(leaderboardID).Result()
 	if err != nil {
-		return fmt.Errorf("Failed to remove leaderboard: %v", err)
+		return fmt.Errorf("Failed to remove leaderboard: %w", err)
 	}
 
 	return nil
 }
 
+// leaderboardFamilyKeys are the suffixes of the auxiliary keys kept for a leaderboard
+var leaderboardFamilyKeys = []string{
+	"ttl", "updates", "joined", "lastupdated", "alltime", "peak", "streak", "prevrank", "presence", "leagues",
+	"ranksnap", "version", "created_at", "config", "changelog", "aliases", "cardinality",
+}
+
+// leaderboardFamilyPatterns are the suffixes of the per-member or per-item auxiliary keys kept for a leaderboard
+var leaderboardFamilyPatterns = []string{
+	"history:*", "streak:*", "meta:*", "rankhistory:*", "heatmap:*", "tagindex:*", "snapshot:*", "metrics:*",
+	"archive:*", "sessions:*",
+}
+
+// familyScanBatchSize is the COUNT hint of each SCAN issued by DeleteLeaderboardFamily
+const familyScanBatchSize = 1000
+
+// familyPatternEscaper escapes the glob characters of a leaderboard name so SCAN matches it literally
+var familyPatternEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// DeleteLeaderboardFamily removes the leaderboard along with all of its auxiliary keys, such as member
+// expirations, score history, archives and snapshots. Returns the number of deleted keys.
+// The fixed keys are deleted in one transaction, then the per-member keys are found with SCAN in batches of
+// familyScanBatchSize and each batch is deleted in its own transaction, so redis is never blocked for long
+// but the removal as a whole is not atomic. The member expirations are also unregistered from the expiration
+// worker. SCAN only walks the keys of the node the client is connected to, so on a cluster the per-member
+// keys stored on other nodes are left behind.
+func (c *Client) DeleteLeaderboardFamily(ctx context.Context, leaderboardID string) (int64, error) {
+	cli := c.redisWithTracing(ctx)
+	c.InvalidateCache(leaderboardID)
+
+	keys := []string{leaderboardID}
+	for _, suffix := range leaderboardFamilyKeys {
+		keys = append(keys, fmt.Sprintf("%s:%s", leaderboardID, suffix))
+	}
+	deleted, err := deleteKeys(cli, keys)
+	if err == nil {
+		err = cli.SRem("expiration-sets", fmt.Sprintf("%s:ttl", leaderboardID)).Err()
+	}
+	if err != nil {
+		return deleted, fmt.Errorf("Failed to remove leaderboard family: %v", err)
+	}
+
+	prefix := familyPatternEscaper.Replace(leaderboardID)
+	for _, pattern := range leaderboardFamilyPatterns {
+		var cursor uint64
+		for {
+			pipe := cli.TxPipeline()
+			scanCmd := pipe.Scan(cursor, fmt.Sprintf("%s:%s", prefix, pattern), familyScanBatchSize)
+			_, err = pipe.Exec()
+			if err != nil {
+				return deleted, fmt.Errorf("Failed to remove leaderboard family: %v", err)
+			}
+
+			found, nextCursor := scanCmd.Val()
+			count, err := deleteKeys(cli, found)
+			deleted += count
+			if err != nil {
+				return deleted, fmt.Errorf("Failed to remove leaderboard family: %v", err)
+			}
+
+			cursor = nextCursor
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// deleteKeys deletes the given keys in a single transaction and returns how many of them existed
+func deleteKeys(cli interfaces.RedisClient, keys []string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	pipe := cli.TxPipeline()
+	cmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Del(key)
+	}
+	_, err := pipe.Exec()
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, cmd := range cmds {
+		deleted += cmd.Val()
+	}
+	return deleted, nil
+}
+
 func (c *Client) Ping(ctx context.Context) (string, error) {
 	return c.redisWithTracing(ctx).Ping().Result()
 }
+
+// presenceBitsetSize is the number of bits of the presence bitset built by BuildPresenceBitset
+const presenceBitsetSize = 1 << 24
+
+// presenceBitOffset returns the bit of the presence bitset that represents the given member
+func presenceBitOffset(memberID string) int64 {
+	hash := sha1.Sum([]byte(memberID))
+	offset, _ := strconv.ParseInt(hex.EncodeToString(hash[:])[:7], 16, 64)
+	return offset % presenceBitsetSize
+}
+
+// GetMembersBitset returns a map indicating which of the given members are present in the leaderboard
+func (c *Client) GetMembersBitset(ctx context.Context, leaderboardID string, memberIDs []string) (map[string]bool, error) {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	cmds := make([]*redis.FloatCmd, len(memberIDs))
+	for i, memberID := range memberIDs {
+		cmds[i] = pipe.ZScore(leaderboardID, memberID)
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Checking members presence failed: %v", err)
+	}
+
+	presence := make(map[string]bool, len(memberIDs))
+	for i, memberID := range memberIDs {
+		presence[memberID] = cmds[i].Err() == nil
+	}
+	return presence, nil
+}
+
+// BuildPresenceBitset stores in <leaderboard>:presence a bitset of the hashed IDs of all current members,
+// allowing probabilistic presence checks with MemberInPresenceBitset. False positives are possible,
+// false negatives are not (as long as the bitset is rebuilt after members are added).
+func (c *Client) BuildPresenceBitset(ctx context.Context, leaderboardID string) error {
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the size of the bitset
+
+		local presence_key = KEYS[1]..":presence"
+		local size = tonumber(ARGV[1])
+		redis.call("DEL", presence_key)
+		local members = redis.call("ZRANGE", KEYS[1], 0, -1)
+		for i,publicID in ipairs(members) do
+			local offset = tonumber(string.sub(redis.sha1hex(publicID), 1, 7), 16) % size
+			redis.call("SETBIT", presence_key, offset, 1)
+		end
+		return #members
+	`)
+
+	_, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, presenceBitsetSize).Result()
+	if err != nil {
+		return fmt.Errorf("Building presence bitset failed: %v", err)
+	}
+	return nil
+}
+
+// MemberInPresenceBitset returns whether the member may be present in the leaderboard, according to the
+// bitset built by BuildPresenceBitset
+func (c *Client) MemberInPresenceBitset(ctx context.Context, leaderboardID string, memberID string) (bool, error) {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	cmd := pipe.GetBit(leaderboardID+":presence", presenceBitOffset(memberID))
+	_, err := pipe.Exec()
+	if err != nil {
+		return false, fmt.Errorf("Checking presence bitset failed: %v", err)
+	}
+	return cmd.Val() == 1, nil
+}
+
+// LeaderboardSummary is an overview of a leaderboard from the point of view of a member
+type LeaderboardSummary struct {
+	TotalMembers  int       `json:"totalMembers"`
+	CurrentMember *Member   `json:"currentMember"`
+	TopMembers    []*Member `json:"topMembers"`
+	Neighbours    []*Member `json:"neighbours"`
+}
+
+// membersFromScoreList converts a flat publicID, score list returned by a WITHSCORES command
+// into members ranked sequentially starting at startRank
+func membersFromScoreList(values []interface{}, startRank int) []*Member {
+	members := make([]*Member, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		score, _ := strconv.ParseInt(values[i+1].(string), 10, 64)
+		members = append(members, &Member{
+			PublicID: values[i].(string),
+			Score:    score,
+			Rank:     startRank + i/2,
+		})
+	}
+	return members
+}
+
+// GetLeaderboardSummary returns the total of members, the member itself, the top 3 members and a page of
+// pageSize members centered in the member with the given ID, using a single round trip.
+// If the member is not in the leaderboard CurrentMember is nil and Neighbours is empty.
+func (c *Client) GetLeaderboardSummary(ctx context.Context, leaderboardID string, memberID string, pageSize int,
+	order string) (*LeaderboardSummary, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	var operations = map[string]string{
+		"range_desc": "ZREVRANGE",
+		"rank_desc":  "ZREVRANK",
+		"range_asc":  "ZRANGE",
+		"rank_asc":   "ZRANK",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is member's public ID
+		-- ARGV[2] is the number of neighbours to be returned
+
+		local total = redis.call("ZCARD", KEYS[1])
+		local top = redis.call("` + operations["range_"+order] + `", KEYS[1], 0, 2, "WITHSCORES")
+		local rank = redis.call("` + operations["rank_"+order] + `", KEYS[1], ARGV[1])
+		if not rank then
+			return {total, top, false, false, 0, {}}
+		end
+
+		local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+		local page_size = tonumber(ARGV[2])
+		local start_offset = rank - math.floor(page_size / 2)
+		if start_offset < 0 then
+			start_offset = 0
+		end
+		local end_offset = start_offset + page_size - 1
+		if end_offset >= total then
+			end_offset = total - 1
+			start_offset = math.max(end_offset - page_size + 1, 0)
+		end
+		local neighbours = redis.call("` + operations["range_"+order] + `", KEYS[1], start_offset, end_offset, "WITHSCORES")
+		return {total, top, rank, score, start_offset, neighbours}
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, memberID, pageSize).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Getting leaderboard summary failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	summary := &LeaderboardSummary{
+		TotalMembers: int(res[0].(int64)),
+		TopMembers:   membersFromScoreList(res[1].([]interface{}), 1),
+		Neighbours:   membersFromScoreList(res[5].([]interface{}), int(res[4].(int64))+1),
+	}
+	if res[2] != nil {
+		score, _ := strconv.ParseInt(res[3].(string), 10, 64)
+		summary.CurrentMember = &Member{PublicID: memberID, Score: score, Rank: int(res[2].(int64)) + 1}
+	}
+	return summary, nil
+}
+
+// GetRankBand returns the best and worst ranks the member with the given ID would hold for a score
+// anywhere in the interval between its current score and its current score plus scoreDelta
+func (c *Client) GetRankBand(ctx context.Context, leaderboardID string, memberID string, scoreDelta int64,
+	order string) (int, int, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is member's public ID
+		-- ARGV[2] is the score delta
+		-- ARGV[3] is the order of the leaderboard
+
+		local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+		if not score then
+			return false
+		end
+		score = tonumber(score)
+		local target = score + tonumber(ARGV[2])
+
+		-- the rank for a given score is the number of members strictly ahead of it plus one
+		local function rank_at(s)
+			if ARGV[3] == "desc" then
+				return redis.call("ZCOUNT", KEYS[1], "(" .. s, "+inf") + 1
+			end
+			return redis.call("ZCOUNT", KEYS[1], "-inf", "(" .. s) + 1
+		end
+
+		local current_rank = rank_at(score)
+		local target_rank = rank_at(target)
+		-- the member itself is ahead of the target score when moving backwards
+		if (ARGV[3] == "desc" and target < score) or (ARGV[3] == "asc" and target > score) then
+			target_rank = target_rank - 1
+		end
+		return {current_rank, target_rank}
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, memberID, scoreDelta, order).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return -1, -1, NewMemberNotFound(leaderboardID, memberID)
+		}
+		return -1, -1, fmt.Errorf("Getting rank band failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	currentRank := int(res[0].(int64))
+	targetRank := int(res[1].(int64))
+	if targetRank < currentRank {
+		return targetRank, currentRank, nil
+	}
+	return currentRank, targetRank, nil
+}
+
+// giniCoefficient calculates the Gini coefficient of the given scores. Scores are sorted first,
+// which costs O(N log N), so the coefficient itself can be computed in a single O(N) pass.
+func giniCoefficient(scores []float64) float64 {
+	n := len(scores)
+	if n < 2 {
+		return 0.0
+	}
+
+	sorted := make([]float64, n)
+	copy(sorted, scores)
+	sort.Float64s(sorted)
+	if sorted[0] == sorted[n-1] {
+		return 0.0
+	}
+
+	var sum, weightedSum float64
+	for i, score := range sorted {
+		sum += score
+		weightedSum += float64(i+1) * score
+	}
+	if sum == 0 {
+		return 0.0
+	}
+	return (2*weightedSum)/(float64(n)*sum) - float64(n+1)/float64(n)
+}
+
+// GetLeaderboardGini returns the Gini coefficient of the scores in the leaderboard,
+// 0.0 meaning all members have the same score
+func (c *Client) GetLeaderboardGini(ctx context.Context, leaderboardID string) (float64, error) {
+	values, err := c.redisWithTracing(ctx).ZRangeWithScores(leaderboardID, 0, -1).Result()
+	if err != nil {
+		return 0.0, fmt.Errorf("Retrieval of leaderboard scores failed: %v", err)
+	}
+
+	scores := make([]float64, len(values))
+	for i, value := range values {
+		scores[i] = value.Score
+	}
+	return giniCoefficient(scores), nil
+}
+
+// ImportRowError describes why a single row of an import failed
+type ImportRowError struct {
+	Row      int
+	PublicID string
+	Err      error
+}
+
+// ImportError indicates some rows of an import could not be stored in the leaderboard
+type ImportError struct {
+	LeaderboardID string
+	Failures      []*ImportRowError
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("Failed to import %d rows into leaderboard %s.", len(e.Failures), e.LeaderboardID)
+}
+
+// BatchSetMembersScoreFromCSV sets the scores of the members read from publicID,score CSV rows, calling
+// SetMembersScore once for every batchSize rows. Failing rows do not abort the import, they are
+// reported in an ImportError together with the number of members that were imported.
+func (c *Client) BatchSetMembersScoreFromCSV(ctx context.Context, leaderboardID string, r io.Reader, scoreTTL string,
+	batchSize int) (int64, error) {
+	if batchSize < 1 {
+		return 0, fmt.Errorf("Batch size must be a positive integer.")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var imported int64
+	importErr := &ImportError{LeaderboardID: leaderboardID}
+	batch := Members{}
+	batchRows := []int{}
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := c.SetMembersScore(ctx, leaderboardID, batch, false, scoreTTL)
+		if err != nil {
+			for i, member := range batch {
+				importErr.Failures = append(importErr.Failures, &ImportRowError{Row: batchRows[i], PublicID: member.PublicID, Err: err})
+			}
+		} else {
+			imported += int64(len(batch))
+		}
+		batch = Members{}
+		batchRows = []int{}
+	}
+
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				flush()
+				return imported, fmt.Errorf("Reading CSV failed: %v", err)
+			}
+			importErr.Failures = append(importErr.Failures, &ImportRowError{Row: row, Err: err})
+			continue
+		}
+
+		score, err := strconv.ParseInt(record[1], 10, 64)
+		if err != nil {
+			importErr.Failures = append(importErr.Failures, &ImportRowError{Row: row, PublicID: record[0], Err: err})
+			continue
+		}
+
+		batch = append(batch, &Member{PublicID: record[0], Score: score})
+		batchRows = append(batchRows, row)
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	if len(importErr.Failures) > 0 {
+		return imported, importErr
+	}
+	return imported, nil
+}
+
+// leaderboardSeasonKeys are the suffixes of the auxiliary keys holding member data of a leaderboard, which
+// RotateLeaderboard moves to the archive along with the members
+var leaderboardSeasonKeys = []string{
+	"updates", "joined", "lastupdated", "alltime", "peak", "streak", "prevrank", "ranksnap", "changelog",
+	"cardinality",
+}
+
+// RotateLeaderboard atomically moves the leaderboard to <leaderboard>:archive:<timestamp>-<id>, leaving the
+// original leaderboard empty, and returns the archive ID. The auxiliary keys holding member data, such as
+// last update times and peak scores, are moved to the archive too, while score TTLs are dropped and
+// unregistered from the expiration worker. Per member keys, such as score histories, are not moved. The
+// archive expires after archiveTTL (no expiration if archiveTTL is 0) and can be queried like any other
+// leaderboard.
+func (c *Client) RotateLeaderboard(ctx context.Context, leaderboardID string, archiveTTL time.Duration) (string, error) {
+	archivedID := fmt.Sprintf("%s:archive:%d-%s", leaderboardID, time.Now().Unix(), uuid.NewV4().String()[:8])
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- KEYS[2] is the name of the archive
+		-- ARGV[1] is the archive ttl in seconds
+		-- ARGV[2...] are the suffixes of the auxiliary keys moved to the archive
+
+		if redis.call("EXISTS", KEYS[1]) == 0 then
+			return redis.error_reply("Leaderboard " .. KEYS[1] .. " does not exist.")
+		end
+		if redis.call("RENAMENX", KEYS[1], KEYS[2]) == 0 then
+			return redis.error_reply("Archive " .. KEYS[2] .. " already exists.")
+		end
+
+		local archived = {KEYS[2]}
+		for i = 2, #ARGV do
+			local key = KEYS[1]..":"..ARGV[i]
+			if redis.call("EXISTS", key) == 1 then
+				redis.call("RENAME", key, KEYS[2]..":"..ARGV[i])
+				table.insert(archived, KEYS[2]..":"..ARGV[i])
+			end
+		end
+		redis.call("DEL", KEYS[1]..":ttl")
+		redis.call("SREM", "expiration-sets", KEYS[1]..":ttl")
+
+		for i,key in ipairs(archived) do
+			redis.call("PERSIST", key)
+			if tonumber(ARGV[1]) > 0 then
+				redis.call("EXPIRE", key, ARGV[1])
+			end
+		end
+		return KEYS[2]
+	`)
+
+	args := []interface{}{int64(archiveTTL / time.Second)}
+	for _, suffix := range leaderboardSeasonKeys {
+		args = append(args, suffix)
+	}
+	c.InvalidateCache(leaderboardID)
+	_, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID, archivedID}, args...).Result()
+	if err != nil {
+		return "", fmt.Errorf("Failed to rotate leaderboard: %v", err)
+	}
+	return archivedID, nil
+}
+
+// rankHistoryMaxEntries is the number of rank snapshots kept for each member
+const rankHistoryMaxEntries = 30
+
+// RankEvent is the rank of a member at a given time
+type RankEvent struct {
+	Timestamp int64 `json:"timestamp"`
+	Rank      int   `json:"rank"`
+}
+
+// RecordRankSnapshot stores the current rank of the member with the given ID in its rank history,
+// keeping only the latest 30 snapshots
+func (c *Client) RecordRankSnapshot(ctx context.Context, leaderboardID string, memberID string, order string) error {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	var operations = map[string]string{
+		"rank_desc": "ZREVRANK",
+		"rank_asc":  "ZRANK",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- KEYS[2] is the name of the member's rank history
+		-- ARGV[1] is member's public ID
+		-- ARGV[2] is the current unix timestamp
+		-- ARGV[3] is the maximum number of snapshots kept
+
+		local rank = redis.call("` + operations["rank_"+order] + `", KEYS[1], ARGV[1])
+		if not rank then
+			return false
+		end
+		rank = rank + 1
+		redis.call("ZADD", KEYS[2], ARGV[2], ARGV[2] .. ":" .. rank)
+		redis.call("ZREMRANGEBYRANK", KEYS[2], 0, -(tonumber(ARGV[3]) + 1))
+		return rank
+	`)
+
+	historyKey := fmt.Sprintf("%s:rankhistory:%s", leaderboardID, memberID)
+	_, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID, historyKey}, memberID, time.Now().Unix(),
+		rankHistoryMaxEntries).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return NewMemberNotFound(leaderboardID, memberID)
+		}
+		return fmt.Errorf("Recording rank snapshot failed: %v", err)
+	}
+	return nil
+}
+
+// GetRankHistory returns the latest limit rank snapshots of the member with the given ID, oldest first.
+// The limit must be positive.
+func (c *Client) GetRankHistory(ctx context.Context, leaderboardID string, memberID string, limit int) ([]RankEvent, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("Rank history limit must be positive")
+	}
+
+	historyKey := fmt.Sprintf("%s:rankhistory:%s", leaderboardID, memberID)
+	values, err := c.redisWithTracing(ctx).ZRevRangeWithScores(historyKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of rank history failed: %v", err)
+	}
+
+	events := make([]RankEvent, len(values))
+	for i, value := range values {
+		snapshot := value.Member.(string)
+		rank, _ := strconv.Atoi(snapshot[strings.LastIndex(snapshot, ":")+1:])
+		events[len(values)-1-i] = RankEvent{Timestamp: int64(value.Score), Rank: rank}
+	}
+	return events, nil
+}
+
+// MultiLeaderboardUnionRank returns the rank of the member with the given ID as if the given leaderboards
+// were a single one, with the scores of each member summed. The union is stored in
+// union:<sorted leaderboard IDs> for one second, so calls in the same second reuse it.
+func (c *Client) MultiLeaderboardUnionRank(ctx context.Context, memberID string, leaderboardIDs []string,
+	order string) (int, error) {
+	if len(leaderboardIDs) == 0 {
+		return -1, fmt.Errorf("At least one leaderboard must be provided.")
+	}
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	var operations = map[string]string{
+		"rank_desc": "ZREVRANK",
+		"rank_asc":  "ZRANK",
+	}
+
+	sortedIDs := make([]string, len(leaderboardIDs))
+	copy(sortedIDs, leaderboardIDs)
+	sort.Strings(sortedIDs)
+	unionKey := "union:" + strings.Join(sortedIDs, ":")
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the union
+		-- KEYS[2..n] are the names of the leaderboards
+		-- ARGV[1] is member's public ID
+
+		if redis.call("EXISTS", KEYS[1]) == 0 then
+			local leaderboards = {}
+			for i = 2, #KEYS do
+				table.insert(leaderboards, KEYS[i])
+			end
+			redis.call("ZUNIONSTORE", KEYS[1], #leaderboards, unpack(leaderboards))
+			redis.call("EXPIRE", KEYS[1], 1)
+		end
+		return redis.call("` + operations["rank_"+order] + `", KEYS[1], ARGV[1])
+	`)
+
+	keys := append([]string{unionKey}, sortedIDs...)
+	result, err := script.Run(c.redisWithTracing(ctx), keys, memberID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return -1, NewMemberNotFound(strings.Join(sortedIDs, ","), memberID)
+		}
+		return -1, fmt.Errorf("Failed to retrieve rank of member in leaderboards union: %v", err)
+	}
+	return int(result.(int64)) + 1, nil
+}
+
+// CompareAndSetMemberScore sets the score of the member with the given ID to newScore only if its current score
+// is expected. It returns whether the score was set and the current state of the member, so callers
+// can retry using the actual score when the comparison fails. A successful swap is a regular score update,
+// so it is validated, tracked and notified to hooks like SetMemberScore.
+func (c *Client) CompareAndSetMemberScore(ctx context.Context, leaderboardID string, memberID string, expected,
+	newScore int64, scoreTTL string) (bool, *Member, error) {
+	member := &Member{PublicID: memberID, Score: newScore}
+	updated, err := c.setMembersScore(ctx, leaderboardID, Members{member}, scoreTTL, false,
+		setScoreScriptOptions{expectedScores: map[string]int64{memberID: expected}})
+	if err != nil {
+		return false, nil, err
+	}
+	if len(updated) == 1 {
+		return true, member, nil
+	}
+
+	current, err := c.GetMember(ctx, leaderboardID, memberID, "desc", false)
+	if err != nil {
+		return false, nil, err
+	}
+	return false, current, nil
+}
+
+// Podium holds the top 3 members of a leaderboard, any of them is nil if the leaderboard has fewer members
+type Podium struct {
+	Gold   *Member `json:"gold"`
+	Silver *Member `json:"silver"`
+	Bronze *Member `json:"bronze"`
+}
+
+// GetLeaderboardPodium returns the top 3 members of the leaderboard
+func (c *Client) GetLeaderboardPodium(ctx context.Context, leaderboardID string, order string) (*Podium, error) {
+	members, err := getMembersByRange(c.redisWithTracing(ctx), leaderboardID, 0, 2, order)
+	if err != nil {
+		return nil, err
+	}
+
+	podium := &Podium{}
+	for i, medal := range []**Member{&podium.Gold, &podium.Silver, &podium.Bronze} {
+		if i < len(members) {
+			*medal = members[i]
+		}
+	}
+	return podium, nil
+}
+
+// GetLeadersExcluding returns a page of members with rank and score skipping the members with the given IDs.
+// Pages are computed over the filtered list but ranks are the global position of each member.
+func (c *Client) GetLeadersExcluding(ctx context.Context, leaderboardID string, pageSize, page int, order string,
+	excludedIDs []string) ([]*Member, error) {
+	if page < 1 {
+		page = 1
+	}
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	var operations = map[string]string{
+		"range_desc": "ZREVRANGE",
+		"range_asc":  "ZRANGE",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the number of members to be skipped
+		-- ARGV[2] is the page size
+		-- ARGV[3..n] are the excluded public IDs
+
+		local excluded = {}
+		for i = 3, #ARGV do
+			excluded[ARGV[i]] = true
+		end
+
+		local to_skip = tonumber(ARGV[1])
+		local page_size = tonumber(ARGV[2])
+		local chunk_size = math.max(page_size * 2, 100)
+		local offset = 0
+		local found = 0
+		local members = {}
+		while found < page_size do
+			local values = redis.call("` + operations["range_"+order] + `", KEYS[1], offset, offset + chunk_size - 1, "WITHSCORES")
+			if #values == 0 then
+				break
+			end
+			for i = 1, #values, 2 do
+				if found < page_size and not excluded[values[i]] then
+					if to_skip > 0 then
+						to_skip = to_skip - 1
+					else
+						table.insert(members, values[i])
+						table.insert(members, offset + (i - 1) / 2)
+						table.insert(members, values[i + 1])
+						found = found + 1
+					end
+				end
+			end
+			offset = offset + chunk_size
+		end
+		return members
+	`)
+
+	args := []interface{}{(page - 1) * pageSize, pageSize}
+	for _, excludedID := range excludedIDs {
+		args = append(args, excludedID)
+	}
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, args...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Getting leaders excluding members failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	members := make([]*Member, 0, len(res)/3)
+	for i := 0; i < len(res); i += 3 {
+		score, _ := strconv.ParseInt(res[i+2].(string), 10, 64)
+		members = append(members, &Member{
+			PublicID: res[i].(string),
+			Score:    score,
+			Rank:     int(res[i+1].(int64)) + 1,
+		})
+	}
+	return members, nil
+}
+
+// RankOutOfBoundsError indicates a rank outside of the range of ranks of a leaderboard was requested
+type RankOutOfBoundsError struct {
+	LeaderboardID string
+	Rank          int
+	TotalMembers  int
+}
+
+func (e *RankOutOfBoundsError) Error() string {
+	return fmt.Sprintf("Rank %d is out of bounds for leaderboard %s with %d members.", e.Rank, e.LeaderboardID, e.TotalMembers)
+}
+
+// GetMemberAtRank returns the member with the given rank, failing with RankOutOfBoundsError
+// if rank is not between 1 and the number of members of the leaderboard
+func (c *Client) GetMemberAtRank(ctx context.Context, leaderboardID string, rank int, order string) (*Member, error) {
+	redisClient := c.redisWithTracing(ctx)
+	totalMembers, err := c.totalMembers(redisClient, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+	if rank < 1 || rank > totalMembers {
+		return nil, &RankOutOfBoundsError{LeaderboardID: leaderboardID, Rank: rank, TotalMembers: totalMembers}
+	}
+
+	members, err := getMembersByRange(redisClient, leaderboardID, rank-1, rank-1, order)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		// the leaderboard shrank between both calls
+		return nil, &RankOutOfBoundsError{LeaderboardID: leaderboardID, Rank: rank, TotalMembers: rank - 1}
+	}
+	return members[0], nil
+}
+
+// BackfillRanks sets, in place, the rank of the given members that have no rank (Rank == 0) without touching
+// their scores. Members that are not in the leaderboard are left without rank.
+func (c *Client) BackfillRanks(ctx context.Context, leaderboardID string, members Members, order string) error {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	var operations = map[string]string{
+		"rank_desc": "ZREVRANK",
+		"rank_asc":  "ZRANK",
+	}
+
+	missing := Members{}
+	args := []interface{}{}
+	for _, member := range members {
+		if member.Rank == 0 {
+			missing = append(missing, member)
+			args = append(args, member.PublicID)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV are the members' public IDs
+
+		local ranks = {}
+		for i,publicID in ipairs(ARGV) do
+			table.insert(ranks, redis.call("` + operations["rank_"+order] + `", KEYS[1], publicID) or -1)
+		end
+		return ranks
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, args...).Result()
+	if err != nil {
+		return fmt.Errorf("Backfilling members rank failed: %v", err)
+	}
+
+	for i, rank := range result.([]interface{}) {
+		missing[i].Rank = int(rank.(int64)) + 1
+	}
+	return nil
+}
+
+// GetLeaderboardQuartiles returns the scores at the first, second and third quartiles of the leaderboard, using the
+// nearest-rank method (the k-th quartile is the score at ascending position ceil(k * total / 4)).
+// It fails if the leaderboard has fewer than 4 members.
+func (c *Client) GetLeaderboardQuartiles(ctx context.Context, leaderboardID string) (int64, int64, int64, error) {
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+
+		local total = redis.call("ZCARD", KEYS[1])
+		if total < 4 then
+			return redis.error_reply("Leaderboard " .. KEYS[1] .. " has fewer than 4 members.")
+		end
+
+		local quartiles = {}
+		for k = 1, 3 do
+			local offset = math.ceil(k * total / 4) - 1
+			local values = redis.call("ZRANGE", KEYS[1], offset, offset, "WITHSCORES")
+			table.insert(quartiles, values[2])
+		end
+		return quartiles
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}).Result()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Getting leaderboard quartiles failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	quartiles := make([]int64, 3)
+	for i := range quartiles {
+		quartiles[i], _ = strconv.ParseInt(res[i].(string), 10, 64)
+	}
+	return quartiles[0], quartiles[1], quartiles[2], nil
+}
+
+// GetGlobalRankAmongLeaderboards returns the rank of the member with the given ID among the members of all the
+// given leaderboards combined, along with the total number of entries in them. If the member is in more than one
+// of the leaderboards its best score is used.
+func (c *Client) GetGlobalRankAmongLeaderboards(ctx context.Context, memberID string, leaderboardIDs []string,
+	order string) (int, int, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	redisClient := c.redisWithTracing(ctx)
+
+	pipe := redisClient.TxPipeline()
+	scoreCmds := make([]*redis.FloatCmd, len(leaderboardIDs))
+	for i, leaderboardID := range leaderboardIDs {
+		scoreCmds[i] = pipe.ZScore(leaderboardID, memberID)
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return -1, -1, fmt.Errorf("Retrieval of member scores failed: %v", err)
+	}
+
+	found := false
+	var best float64
+	for _, cmd := range scoreCmds {
+		if cmd.Err() != nil {
+			continue
+		}
+		if !found || (order == "desc" && cmd.Val() > best) || (order == "asc" && cmd.Val() < best) {
+			best = cmd.Val()
+		}
+		found = true
+	}
+	if !found {
+		return -1, -1, NewMemberNotFound(strings.Join(leaderboardIDs, ","), memberID)
+	}
+
+	minScore, maxScore := "("+strconv.FormatFloat(best, 'f', -1, 64), "+inf"
+	if order == "asc" {
+		minScore, maxScore = "-inf", "("+strconv.FormatFloat(best, 'f', -1, 64)
+	}
+	pipe = redisClient.TxPipeline()
+	aheadCmds := make([]*redis.IntCmd, len(leaderboardIDs))
+	totalCmds := make([]*redis.IntCmd, len(leaderboardIDs))
+	for i, leaderboardID := range leaderboardIDs {
+		aheadCmds[i] = pipe.ZCount(leaderboardID, minScore, maxScore)
+		totalCmds[i] = pipe.ZCard(leaderboardID)
+	}
+	_, err = pipe.Exec()
+	if err != nil {
+		return -1, -1, fmt.Errorf("Counting members across leaderboards failed: %v", err)
+	}
+
+	rank, total := 1, 0
+	for i := range leaderboardIDs {
+		rank += int(aheadCmds[i].Val())
+		total += int(totalCmds[i].Val())
+	}
+	return rank, total, nil
+}
+
+// sessionScoresMaxEntries is the number of session scores kept for each member by RecordSessionScore
+const sessionScoresMaxEntries = 20
+
+// RecordSessionScore stores the score of a session of the member with the given ID, keeping the latest 20 of them,
+// and sets the member's score in the leaderboard to the average of the stored session scores. The average is set
+// like SetMemberScore does, so if the score validator rejects it the session score is still stored.
+func (c *Client) RecordSessionScore(ctx context.Context, leaderboardID string, memberID string, sessionScore int64) error {
+	expireAt, err := util.GetExpireAt(leaderboardID)
+	if err != nil {
+		if _, ok := err.(*util.LeaderboardExpiredError); ok {
+			return err
+		}
+		return fmt.Errorf("Could not get expiration: %v", err)
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the member's session scores list
+		-- ARGV[1] is the session score
+		-- ARGV[2] is the maximum number of session scores kept
+		-- ARGV[3] is the leaderboard's expiration
+
+		redis.call("LPUSH", KEYS[1], ARGV[1])
+		redis.call("LTRIM", KEYS[1], 0, tonumber(ARGV[2]) - 1)
+		if ARGV[3] ~= "-1" and redis.call("TTL", KEYS[1]) == -1 then
+			redis.call("EXPIREAT", KEYS[1], ARGV[3])
+		end
+
+		local scores = redis.call("LRANGE", KEYS[1], 0, -1)
+		local sum = 0
+		for i,score in ipairs(scores) do
+			sum = sum + tonumber(score)
+		end
+		return math.floor(sum / #scores + 0.5)
+	`)
+
+	sessionsKey := fmt.Sprintf("%s:sessions:%s", leaderboardID, memberID)
+	average, err := script.Run(c.redisWithTracing(ctx), []string{sessionsKey}, sessionScore, sessionScoresMaxEntries,
+		expireAt).Result()
+	if err != nil {
+		return fmt.Errorf("Recording session score failed: %v", err)
+	}
+
+	members := Members{&Member{PublicID: memberID, Score: average.(int64)}}
+	_, err = c.setMembersScore(ctx, leaderboardID, members, "", false, setScoreScriptOptions{})
+	return err
+}
+
+// GetMemberAverageScore returns the average of the session scores stored for the member with the given ID
+func (c *Client) GetMemberAverageScore(ctx context.Context, leaderboardID string, memberID string) (float64, error) {
+	sessionsKey := fmt.Sprintf("%s:sessions:%s", leaderboardID, memberID)
+	scores, err := c.redisWithTracing(ctx).LRange(sessionsKey, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("Retrieval of session scores failed: %v", err)
+	}
+	if len(scores) == 0 {
+		return 0, NewMemberNotFound(leaderboardID, memberID)
+	}
+
+	var sum float64
+	for _, score := range scores {
+		value, _ := strconv.ParseFloat(score, 64)
+		sum += value
+	}
+	return sum / float64(len(scores)), nil
+}
+
+// MemberExists returns whether the member with the given ID is in the leaderboard
+func (c *Client) MemberExists(ctx context.Context, leaderboardID string, memberID string) (bool, error) {
+	_, err := c.redisWithTracing(ctx).ZScore(leaderboardID, memberID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, fmt.Errorf("Checking member existence failed: %v", err)
+	}
+	return true, nil
+}
+
+// GetTopMembersAboveScore returns up to n members with score greater than or equal to threshold, with their ranks.
+// In desc order these are the top members of the leaderboard, in asc order the ones closest to the threshold.
+func (c *Client) GetTopMembersAboveScore(ctx context.Context, leaderboardID string, threshold int64, n int,
+	order string) ([]*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the score threshold
+		-- ARGV[2] is the maximum number of members returned
+		-- ARGV[3] is the order of the leaderboard
+
+		if ARGV[3] == "desc" then
+			return {0, redis.call("ZREVRANGEBYSCORE", KEYS[1], "+inf", ARGV[1], "WITHSCORES", "LIMIT", 0, ARGV[2])}
+		end
+		local start_offset = redis.call("ZCOUNT", KEYS[1], "-inf", "(" .. ARGV[1])
+		return {start_offset, redis.call("ZRANGEBYSCORE", KEYS[1], ARGV[1], "+inf", "WITHSCORES", "LIMIT", 0, ARGV[2])}
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, threshold, n, order).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Getting members above score failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	return membersFromScoreList(res[1].([]interface{}), int(res[0].(int64))+1), nil
+}
+
+// DensityBucket holds the number of members with scores between MinScore and MaxScore (inclusive)
+// and the number of members per score unit in that interval
+type DensityBucket struct {
+	MinScore int64   `json:"minScore"`
+	MaxScore int64   `json:"maxScore"`
+	Count    int64   `json:"count"`
+	Density  float64 `json:"density"`
+}
+
+// GetLeaderboardDensity divides the range between the lowest and the highest scores of the leaderboard in the
+// given number of buckets and returns how many members are in each of them. Fewer buckets are returned if the
+// range has fewer score units than buckets.
+func (c *Client) GetLeaderboardDensity(ctx context.Context, leaderboardID string, buckets int) ([]DensityBucket, error) {
+	if buckets < 1 {
+		return nil, fmt.Errorf("Number of buckets must be a positive integer.")
+	}
+
+	values, err := c.redisWithTracing(ctx).ZRangeWithScores(leaderboardID, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of leaderboard scores failed: %v", err)
+	}
+	if len(values) == 0 {
+		return []DensityBucket{}, nil
+	}
+
+	minScore := int64(values[0].Score)
+	maxScore := int64(values[len(values)-1].Score)
+	span := maxScore - minScore + 1
+	if span < int64(buckets) {
+		buckets = int(span)
+	}
+
+	density := make([]DensityBucket, buckets)
+	for i := range density {
+		density[i].MinScore = minScore + int64(i)*span/int64(buckets)
+		density[i].MaxScore = minScore + int64(i+1)*span/int64(buckets) - 1
+	}
+
+	// scores are sorted, so buckets are filled in order
+	bucket := 0
+	for _, value := range values {
+		for int64(value.Score) > density[bucket].MaxScore {
+			bucket++
+		}
+		density[bucket].Count++
+	}
+	for i := range density {
+		density[i].Density = float64(density[i].Count) / float64(density[i].MaxScore-density[i].MinScore+1)
+	}
+	return density, nil
+}
+
+// GetMembersWithZeroScore returns up to limit members whose score is zero, with their rank in desc order
+func (c *Client) GetMembersWithZeroScore(ctx context.Context, leaderboardID string, limit int64) ([]*Member, error) {
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the maximum number of members returned
+
+		local start_offset = redis.call("ZCOUNT", KEYS[1], "(0", "+inf")
+		return {start_offset, redis.call("ZREVRANGEBYSCORE", KEYS[1], 0, 0, "WITHSCORES", "LIMIT", 0, ARGV[1])}
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, limit).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Getting members with zero score failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	return membersFromScoreList(res[1].([]interface{}), int(res[0].(int64))+1), nil
+}
+
+// CountMembersWithZeroScore returns the number of members whose score is zero
+func (c *Client) CountMembersWithZeroScore(ctx context.Context, leaderboardID string) (int64, error) {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	cmd := pipe.ZCount(leaderboardID, "0", "0")
+	_, err := pipe.Exec()
+	if err != nil {
+		return 0, fmt.Errorf("Counting members with zero score failed: %v", err)
+	}
+	return cmd.Val(), nil
+}
+
+// TopScoreEverEnabled returns whether the highest score ever reached in each leaderboard is kept
+func (c *Client) TopScoreEverEnabled() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.topScoreEver
+}
+
+// SetTopScoreEverEnabled sets whether the highest score ever reached in each leaderboard is kept
+func (c *Client) SetTopScoreEverEnabled(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.topScoreEver = enabled
+}
+
+// RecordTopScoreEver stores the current top score of the leaderboard as its all-time top score if it is higher
+// than the recorded one. With WithTopScoreEver, setting or incrementing scores already keeps the all-time top
+// score, so this is only needed for scores written before it was tracked.
+func (c *Client) RecordTopScoreEver(ctx context.Context, leaderboardID string) error {
+	expireAt, err := util.GetExpireAt(leaderboardID)
+	if err != nil {
+		if _, ok := err.(*util.LeaderboardExpiredError); ok {
+			return err
+		}
+		return fmt.Errorf("Could not get expiration: %v", err)
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the leaderboard's expiration
+
+		local top = redis.call("ZREVRANGE", KEYS[1], 0, 0, "WITHSCORES")
+		if #top == 0 then
+			return 0
+		end
+		local record = redis.call("ZREVRANGE", KEYS[1]..":alltime", 0, 0, "WITHSCORES")
+		if #record == 0 or tonumber(top[2]) > tonumber(record[2]) then
+			redis.call("ZADD", KEYS[1]..":alltime", top[2], top[1])
+			if ARGV[1] ~= "-1" and redis.call("TTL", KEYS[1]..":alltime") == -1 then
+				redis.call("EXPIREAT", KEYS[1]..":alltime", ARGV[1])
+			end
+			return 1
+		end
+		return 0
+	`)
+
+	_, err = script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, expireAt).Result()
+	if err != nil {
+		return fmt.Errorf("Recording top score ever failed: %v", err)
+	}
+	return nil
+}
+
+// GetTopScoreEver returns the highest score ever reached in the leaderboard and the ID of the member that
+// reached it, even if the member's score changed or the leaderboard was removed since. Scores are only
+// tracked when the client is created with WithTopScoreEver, and the record expires with the leaderboard.
+// An empty member ID is returned if no score was ever recorded.
+func (c *Client) GetTopScoreEver(ctx context.Context, leaderboardID string) (int64, string, error) {
+	values, err := c.redisWithTracing(ctx).ZRevRangeWithScores(leaderboardID+":alltime", 0, 0).Result()
+	if err != nil {
+		return 0, "", fmt.Errorf("Retrieval of top score ever failed: %v", err)
+	}
+	if len(values) == 0 {
+		return 0, "", nil
+	}
+	return int64(values[0].Score), values[0].Member.(string), nil
+}
+
+// UpdateTrackingWindow returns for how long member updates are kept, 0 if they are not kept
+func (c *Client) UpdateTrackingWindow() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.updatesWindow
+}
+
+// SetUpdateTrackingWindow sets for how long member updates are kept, 0 to stop keeping them
+func (c *Client) SetUpdateTrackingWindow(window time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.updatesWindow = window
+}
+
+// GetMembersChangedSince returns the current score and rank of the members whose score was set or incremented
+// at or after the given time. Updates are tracked with a precision of one second, so members updated in the
+// same second as t are always included. Updates are only kept with WithUpdateTracking, and only within its
+// window, so earlier times return the members updated within the window.
+func (c *Client) GetMembersChangedSince(ctx context.Context, leaderboardID string, t time.Time, order string) ([]*Member, error) {
+	memberIDs, err := c.redisWithTracing(ctx).ZRangeByScore(leaderboardID+":updates", redis.ZRangeBy{
+		Min: strconv.FormatInt(t.Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of updated members failed: %v", err)
+	}
+	if len(memberIDs) == 0 {
+		return []*Member{}, nil
+	}
+
+	return c.GetMembers(ctx, leaderboardID, memberIDs, order, false, false)
+}
+
+// CursorPage is a page of members returned by GetLeaderboardPageWithCursor
+type CursorPage struct {
+	Members    []*Member `json:"members"`
+	NextCursor string    `json:"nextCursor"`
+	HasMore    bool      `json:"hasMore"`
+}
+
+// pageCursor is the position of the last member of a page, encoded in the cursor returned with it. TieOffset
+// is how many members with the score of the last member were already returned, including it.
+type pageCursor struct {
+	Score     int64  `json:"score"`
+	PublicID  string `json:"publicID"`
+	TieOffset int    `json:"tieOffset"`
+}
+
+// GetLeaderboardPageWithCursor returns the page of pageSize members that follows the given cursor, or the
+// first page if cursor is empty. Pages continue right after the last member seen instead of from an offset, so
+// members added or removed ahead of the cursor do not make members be skipped or repeated. If the last member
+// seen was removed or its score changed, the page continues from its score, skipping the members tied with it
+// that were already returned.
+func (c *Client) GetLeaderboardPageWithCursor(ctx context.Context, leaderboardID string, cursor string, pageSize int,
+	order string) (*CursorPage, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	var last pageCursor
+	hasCursor := cursor != ""
+	if hasCursor {
+		decoded, err := base64.URLEncoding.DecodeString(cursor)
+		if err == nil {
+			err = json.Unmarshal(decoded, &last)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid cursor %s: %v", cursor, err)
+		}
+	}
+
+	var operations = map[string]string{
+		"range_desc":          "ZREVRANGE",
+		"rank_desc":           "ZREVRANK",
+		"range_by_score_desc": "ZREVRANGEBYSCORE",
+		"range_asc":           "ZRANGE",
+		"rank_asc":            "ZRANK",
+		"range_by_score_asc":  "ZRANGEBYSCORE",
+	}
+	scoreLimit := map[string]string{"desc": "-inf", "asc": "+inf"}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] defines if a cursor was given
+		-- ARGV[2] is the score of the last member seen
+		-- ARGV[3] is the public ID of the last member seen
+		-- ARGV[4] is the number of members to be fetched
+		-- ARGV[5] is the order of the page, desc or asc
+		-- ARGV[6] is how many members tied with the last member seen were already returned
+
+		local wanted = tonumber(ARGV[4])
+		local members = {}
+		if ARGV[1] ~= "1" then
+			members = redis.call("` + operations["range_"+order] + `", KEYS[1], 0, wanted - 1, "WITHSCORES")
+		elseif tonumber(redis.call("ZSCORE", KEYS[1], ARGV[3])) == tonumber(ARGV[2]) then
+			-- the last member seen is still in place, the page starts right after it
+			local start = redis.call("` + operations["rank_"+order] + `", KEYS[1], ARGV[3]) + 1
+			members = redis.call("` + operations["range_"+order] + `", KEYS[1], start, start + wanted - 1,
+				"WITHSCORES")
+		else
+			-- members tied with the last one seen are ordered by public ID, those before it were already seen
+			local last_score = tonumber(ARGV[2])
+			local offset = math.max(tonumber(ARGV[6]) - 1, 0)
+			local values = redis.call("` + operations["range_by_score_"+order] + `", KEYS[1], ARGV[2], "` +
+		scoreLimit[order] + `", "WITHSCORES", "LIMIT", offset, wanted * 2)
+			for i = 1, #values, 2 do
+				local seen = tonumber(values[i + 1]) == last_score and
+					((ARGV[5] == "desc" and values[i] >= ARGV[3]) or (ARGV[5] == "asc" and values[i] <= ARGV[3]))
+				if not seen and #members < wanted * 2 then
+					table.insert(members, values[i])
+					table.insert(members, values[i + 1])
+				end
+			end
+		end
+
+		if #members == 0 then
+			return {0, members}
+		end
+		return {redis.call("` + operations["rank_"+order] + `", KEYS[1], members[1]), members}
+	`)
+
+	// one more member than needed is fetched to know if there are more pages
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, hasCursor, last.Score, last.PublicID,
+		pageSize+1, order, last.TieOffset).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Getting page with cursor failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	members := membersFromScoreList(res[1].([]interface{}), int(res[0].(int64))+1)
+	page := &CursorPage{Members: members, NextCursor: cursor}
+	if len(members) > pageSize {
+		page.Members = members[:pageSize]
+		page.HasMore = true
+	}
+	if len(page.Members) > 0 {
+		lastMember := page.Members[len(page.Members)-1]
+		next := pageCursor{Score: lastMember.Score, PublicID: lastMember.PublicID}
+		for i := len(page.Members) - 1; i >= 0 && page.Members[i].Score == lastMember.Score; i-- {
+			next.TieOffset++
+		}
+		if next.TieOffset == len(page.Members) && hasCursor && last.Score == lastMember.Score {
+			next.TieOffset += last.TieOffset
+		}
+		encoded, _ := json.Marshal(next)
+		page.NextCursor = base64.URLEncoding.EncodeToString(encoded)
+	}
+	return page, nil
+}
+
+// GetMemberRelativeRank returns the fraction of the leaderboard the member is ahead of, 1 for the first
+// member and close to 0 for the last one
+func (c *Client) GetMemberRelativeRank(ctx context.Context, leaderboardID string, memberID string,
+	order string) (float64, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	var operations = map[string]string{
+		"rank_desc": "ZREVRANK",
+		"rank_asc":  "ZRANK",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the member's public ID
+
+		local rank = redis.call("` + operations["rank_"+order] + `", KEYS[1], ARGV[1])
+		if not rank then
+			return false
+		end
+		return {rank, redis.call("ZCARD", KEYS[1])}
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, memberID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, NewMemberNotFound(leaderboardID, memberID)
+		}
+		return 0, fmt.Errorf("Retrieval of member relative rank failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	return 1.0 - float64(res[0].(int64))/float64(res[1].(int64)), nil
+}
+
+// GetMemberRelativeRankBatch returns the relative rank of each of the given members, as documented in
+// GetMemberRelativeRank. Members that are not in the leaderboard are left out of the result.
+func (c *Client) GetMemberRelativeRankBatch(ctx context.Context, leaderboardID string, memberIDs []string,
+	order string) (map[string]float64, error) {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	totalCmd := pipe.ZCard(leaderboardID)
+	rankCmds := make([]*redis.IntCmd, len(memberIDs))
+	for i, memberID := range memberIDs {
+		if order == "asc" {
+			rankCmds[i] = pipe.ZRank(leaderboardID, memberID)
+		} else {
+			rankCmds[i] = pipe.ZRevRank(leaderboardID, memberID)
+		}
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of members relative rank failed: %v", err)
+	}
+
+	relativeRanks := make(map[string]float64, len(memberIDs))
+	for i, memberID := range memberIDs {
+		if rankCmds[i].Err() != nil {
+			continue
+		}
+		relativeRanks[memberID] = 1.0 - float64(rankCmds[i].Val())/float64(totalCmd.Val())
+	}
+	return relativeRanks, nil
+}
+
+// SortMembersByExternalScore returns a copy of members sorted by the given scores, with ranks updated to
+// match the new ordering. Members without an entry in scores are sorted by their own score. Ties keep the
+// original relative order and the given members are not modified.
+func SortMembersByExternalScore(members Members, scores map[string]int64, order string) Members {
+	externalScore := func(member *Member) int64 {
+		if score, ok := scores[member.PublicID]; ok {
+			return score
+		}
+		return member.Score
+	}
+
+	sorted := make(Members, len(members))
+	for i, member := range members {
+		memberCopy := *member
+		sorted[i] = &memberCopy
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if order == "asc" {
+			return externalScore(sorted[i]) < externalScore(sorted[j])
+		}
+		return externalScore(sorted[i]) > externalScore(sorted[j])
+	})
+	for i, member := range sorted {
+		member.Rank = i + 1
+	}
+	return sorted
+}
+
+// GetMembersByIDPattern returns up to limit members whose public IDs match the given glob-style pattern,
+// e.g. "guild_X_*", with their scores and ranks. Members are returned in no particular order.
+// This scans the whole leaderboard, which is O(N), so it must not be used in hot paths.
+func (c *Client) GetMembersByIDPattern(ctx context.Context, leaderboardID string, pattern string, limit int64,
+	order string) ([]*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	var operations = map[string]string{
+		"rank_desc": "ZREVRANK",
+		"rank_asc":  "ZRANK",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the pattern public IDs must match
+		-- ARGV[2] is the maximum number of members to be returned
+
+		local limit = tonumber(ARGV[2])
+		local members = {}
+		local cursor = "0"
+		repeat
+			local scan = redis.call("ZSCAN", KEYS[1], cursor, "MATCH", ARGV[1], "COUNT", 1000)
+			cursor = scan[1]
+			local values = scan[2]
+			for i = 1, #values, 2 do
+				if #members >= limit then
+					return members
+				end
+				local rank = redis.call("` + operations["rank_"+order] + `", KEYS[1], values[i])
+				table.insert(members, {values[i], values[i + 1], rank})
+			end
+		until cursor == "0"
+		return members
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, pattern, limit).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of members by ID pattern failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	members := make([]*Member, len(res))
+	for i, value := range res {
+		memberData := value.([]interface{})
+		score, _ := strconv.ParseFloat(memberData[1].(string), 64)
+		members[i] = &Member{
+			PublicID: memberData[0].(string),
+			Score:    int64(score),
+			Rank:     int(memberData[2].(int64)) + 1,
+		}
+	}
+	return members, nil
+}
+
+// GuildEntry is the aggregated score of the members of a guild
+type GuildEntry struct {
+	GuildID     string `json:"guildID"`
+	TotalScore  int64  `json:"totalScore"`
+	MemberCount int    `json:"memberCount"`
+	Rank        int    `json:"rank"`
+}
+
+// GetGuildLeaderboard ranks guilds by the sum of their members' scores. guildMembership maps member
+// public IDs to guild IDs; members that are not in the leaderboard do not count towards their guild.
+// Guilds with the same total score are ranked by guild ID.
+func (c *Client) GetGuildLeaderboard(ctx context.Context, leaderboardID string, guildMembership map[string]string,
+	order string) ([]*GuildEntry, error) {
+	memberIDs := make([]string, 0, len(guildMembership))
+	for memberID := range guildMembership {
+		memberIDs = append(memberIDs, memberID)
+	}
+
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	scoreCmds := make([]*redis.FloatCmd, len(memberIDs))
+	for i, memberID := range memberIDs {
+		scoreCmds[i] = pipe.ZScore(leaderboardID, memberID)
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of guild member scores failed: %v", err)
+	}
+
+	guilds := map[string]*GuildEntry{}
+	for i, memberID := range memberIDs {
+		if scoreCmds[i].Err() != nil {
+			continue
+		}
+		guildID := guildMembership[memberID]
+		guild, ok := guilds[guildID]
+		if !ok {
+			guild = &GuildEntry{GuildID: guildID}
+			guilds[guildID] = guild
+		}
+		guild.TotalScore += int64(scoreCmds[i].Val())
+		guild.MemberCount++
+	}
+
+	entries := make([]*GuildEntry, 0, len(guilds))
+	for _, guild := range guilds {
+		entries = append(entries, guild)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].TotalScore == entries[j].TotalScore {
+			return entries[i].GuildID < entries[j].GuildID
+		}
+		if order == "asc" {
+			return entries[i].TotalScore < entries[j].TotalScore
+		}
+		return entries[i].TotalScore > entries[j].TotalScore
+	})
+	for i, entry := range entries {
+		entry.Rank = i + 1
+	}
+	return entries, nil
+}
+
+// GetGuildLeaderboardTopN returns the first n guilds as ranked by GetGuildLeaderboard
+func (c *Client) GetGuildLeaderboardTopN(ctx context.Context, leaderboardID string, guildMembership map[string]string,
+	n int, order string) ([]*GuildEntry, error) {
+	entries, err := c.GetGuildLeaderboard(ctx, leaderboardID, guildMembership, order)
+	if err != nil {
+		return nil, err
+	}
+	if n < len(entries) {
+		entries = entries[:n]
+	}
+	return entries, nil
+}
+
+// DeleteMemberTTL removes the score expiration of a member, keeping its score and rank.
+// Returns MemberNotFoundError if the member score had no expiration.
+func (c *Client) DeleteMemberTTL(ctx context.Context, leaderboardID string, memberID string) error {
+	removed, err := c.redisWithTracing(ctx).ZRem(fmt.Sprintf("%s:ttl", leaderboardID), memberID).Result()
+	if err != nil {
+		return fmt.Errorf("Removal of member score expiration failed: %v", err)
+	}
+	if removed == 0 {
+		return NewMemberNotFound(leaderboardID, memberID)
+	}
+	return nil
+}
+
+// GetLeadersWithDecay returns a page of members ranked by their time-decayed scores, computed as
+// score * e^(-lambda * (now - lastUpdated)) where lambda is ln(2) / halfLifeSeconds, so a score is
+// worth half as much after each half-life since its last update, read from the last update times kept on every
+// write. Members without a recorded update time are not decayed. Decayed scores are returned in the Score
+// field and are not persisted. Every call decays and sorts the whole leaderboard in a single script, which is
+// O(N log N) and blocks redis meanwhile, so it should not be used on large leaderboards.
+func (c *Client) GetLeadersWithDecay(ctx context.Context, leaderboardID string, pageSize, page int, order string,
+	halfLifeSeconds int64) ([]*Member, error) {
+	if halfLifeSeconds <= 0 {
+		return nil, fmt.Errorf("Half-life must be a positive number of seconds")
+	}
+	if page < 1 {
+		page = 1
+	}
+	startOffset := (page - 1) * pageSize
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- KEYS[2] is the name of the hash of member last update times
+		-- ARGV[1] is the current unix timestamp
+		-- ARGV[2] is the decay constant
+		-- ARGV[3] is the order of the ranking
+		-- ARGV[4] is the offset of the first member of the page
+		-- ARGV[5] is the size of the page
+
+		local now = tonumber(ARGV[1])
+		local lambda = tonumber(ARGV[2])
+		local values = redis.call("ZRANGE", KEYS[1], 0, -1, "WITHSCORES")
+		local members = {}
+		for i = 1, #values, 2 do
+			local score = tonumber(values[i + 1])
+			local last_updated = redis.call("HGET", KEYS[2], values[i])
+			if last_updated then
+				score = score * math.exp(-lambda * math.max(now - tonumber(last_updated), 0))
+			end
+			table.insert(members, {values[i], score})
+		end
+
+		table.sort(members, function(a, b)
+			if a[2] == b[2] then
+				return a[1] < b[1]
+			end
+			if ARGV[3] == "asc" then
+				return a[2] < b[2]
+			end
+			return a[2] > b[2]
+		end)
+
+		local page = {}
+		local first = tonumber(ARGV[4]) + 1
+		for i = first, math.min(first + tonumber(ARGV[5]) - 1, #members) do
+			table.insert(page, {members[i][1], string.format("%.0f", math.floor(members[i][2])), i})
+		end
+		return page
+	`)
+
+	lambda := math.Ln2 / float64(halfLifeSeconds)
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID, fmt.Sprintf("%s:lastupdated", leaderboardID)},
+		time.Now().Unix(), strconv.FormatFloat(lambda, 'g', -1, 64), order, startOffset, pageSize).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of leaders with decayed scores failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	members := make([]*Member, len(res))
+	for i, value := range res {
+		memberData := value.([]interface{})
+		score, _ := strconv.ParseInt(memberData[1].(string), 10, 64)
+		members[i] = &Member{
+			PublicID: memberData[0].(string),
+			Score:    score,
+			Rank:     int(memberData[2].(int64)),
+		}
+	}
+	return members, nil
+}
+
+// GetMemberLastUpdated returns the time of the last score update of a member.
+// Returns MemberNotFoundError if the member score was never updated.
+func (c *Client) GetMemberLastUpdated(ctx context.Context, leaderboardID string, memberID string) (time.Time, error) {
+	lastUpdated, err := c.redisWithTracing(ctx).HGet(fmt.Sprintf("%s:lastupdated", leaderboardID), memberID).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, NewMemberNotFound(leaderboardID, memberID)
+		}
+		return time.Time{}, fmt.Errorf("Retrieval of member last update failed: %v", err)
+	}
+	return time.Unix(lastUpdated, 0), nil
+}
+
+// BulkGetMembersLastUpdated returns the time of the last score update of each of the given members.
+// Members whose score was never updated are left out of the result.
+func (c *Client) BulkGetMembersLastUpdated(ctx context.Context, leaderboardID string,
+	memberIDs []string) (map[string]time.Time, error) {
+	lastUpdatedTimes := make(map[string]time.Time, len(memberIDs))
+	if len(memberIDs) == 0 {
+		return lastUpdatedTimes, nil
+	}
+
+	values, err := c.redisWithTracing(ctx).HMGet(fmt.Sprintf("%s:lastupdated", leaderboardID), memberIDs...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of members last update failed: %v", err)
+	}
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		lastUpdated, _ := strconv.ParseInt(value.(string), 10, 64)
+		lastUpdatedTimes[memberIDs[i]] = time.Unix(lastUpdated, 0)
+	}
+	return lastUpdatedTimes, nil
+}
+
+// lagPollInterval is how often GetLeaderboardLag checks the replica for the sentinel key
+const lagPollInterval = time.Millisecond
+
+// GetLeaderboardLag measures the replication lag between a primary and a replica by writing a
+// sentinel leaderboard to the primary and polling the replica until it shows up, failing if it
+// takes longer than timeout. The sentinel leaderboard is removed before returning.
+func GetLeaderboardLag(primaryClient, replicaClient interfaces.RedisClient, timeout time.Duration) (time.Duration, error) {
+	sentinelKey := fmt.Sprintf("podium:lag-sentinel:%d", time.Now().UnixNano())
+	start := time.Now()
+	_, err := primaryClient.ZAdd(sentinelKey, redis.Z{Score: float64(start.Unix()), Member: "sentinel"}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("Writing lag sentinel to primary failed: %v", err)
+	}
+	defer primaryClient.Del(sentinelKey)
+
+	for {
+		_, err := replicaClient.ZScore(sentinelKey, "sentinel").Result()
+		if err == nil {
+			return time.Since(start), nil
+		}
+		if err != redis.Nil {
+			return 0, fmt.Errorf("Reading lag sentinel from replica failed: %v", err)
+		}
+		if time.Since(start) > timeout {
+			return 0, fmt.Errorf("Lag sentinel did not reach replica in %v", timeout)
+		}
+		time.Sleep(lagPollInterval)
+	}
+}
+
+// GetNthMember returns the member at the given 1-based rank.
+// Returns RankOutOfBoundsError if n is not between 1 and the number of members.
+func (c *Client) GetNthMember(ctx context.Context, leaderboardID string, n int, order string) (*Member, error) {
+	return c.GetMemberAtRank(ctx, leaderboardID, n, order)
+}
+
+// GetScoreGap returns how many points separate a member from the member ranked right above it, if
+// direction is "up", or right below it, if direction is "down". Returns 0 if there is no such member.
+func (c *Client) GetScoreGap(ctx context.Context, leaderboardID string, memberID string, direction string,
+	order string) (int64, error) {
+	if direction != "up" && direction != "down" {
+		return 0, fmt.Errorf("Invalid direction %s, must be up or down", direction)
+	}
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	var operations = map[string]string{
+		"range_desc": "ZREVRANGE",
+		"rank_desc":  "ZREVRANK",
+		"range_asc":  "ZRANGE",
+		"rank_asc":   "ZRANK",
+	}
+	neighbourOffset := map[string]int{"up": -1, "down": 1}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the member's public ID
+		-- ARGV[2] is the offset from the member's rank to its neighbour's
+
+		local rank = redis.call("` + operations["rank_"+order] + `", KEYS[1], ARGV[1])
+		if not rank then
+			return false
+		end
+		local neighbour_rank = rank + tonumber(ARGV[2])
+		if neighbour_rank < 0 then
+			return 0
+		end
+		local neighbour = redis.call("` + operations["range_"+order] + `", KEYS[1], neighbour_rank, neighbour_rank, "WITHSCORES")
+		if #neighbour == 0 then
+			return 0
+		end
+		return math.abs(tonumber(neighbour[2]) - tonumber(redis.call("ZSCORE", KEYS[1], ARGV[1])))
+	`)
+
+	gap, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, memberID,
+		neighbourOffset[direction]).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, NewMemberNotFound(leaderboardID, memberID)
+		}
+		return 0, fmt.Errorf("Retrieval of score gap failed: %v", err)
+	}
+	return gap.(int64), nil
+}
+
+// GetMembersMultiPage returns the pages from startPage to endPage, both included, fetching all of them
+// in a single range query. Pages past the last one of the leaderboard are not returned.
+func (c *Client) GetMembersMultiPage(ctx context.Context, leaderboardID string, pageSize, startPage, endPage int,
+	order string) ([][]*Member, error) {
+	if startPage < 1 {
+		startPage = 1
+	}
+	if endPage < startPage {
+		return [][]*Member{}, nil
+	}
+
+	startOffset := (startPage - 1) * pageSize
+	endOffset := endPage*pageSize - 1
+	members, err := getMembersByRange(c.redisWithTracing(ctx), leaderboardID, startOffset, endOffset, order)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := [][]*Member{}
+	for start := 0; start < len(members); start += pageSize {
+		end := start + pageSize
+		if end > len(members) {
+			end = len(members)
+		}
+		pages = append(pages, members[start:end])
+	}
+	return pages, nil
+}
+
+// GetMembersAboveRank returns up to limit members ranked right above the given rank, or all of them if
+// limit is -1
+func (c *Client) GetMembersAboveRank(ctx context.Context, leaderboardID string, rank, limit int,
+	order string) ([]*Member, error) {
+	if rank <= 1 || limit == 0 {
+		return []*Member{}, nil
+	}
+
+	startOffset := 0
+	endOffset := rank - 2
+	if limit != -1 && endOffset-limit+1 > 0 {
+		startOffset = endOffset - limit + 1
+	}
+	return getMembersByRange(c.redisWithTracing(ctx), leaderboardID, startOffset, endOffset, order)
+}
+
+// GetMembersBelowRank returns up to limit members ranked right below the given rank, or all of them if
+// limit is -1
+func (c *Client) GetMembersBelowRank(ctx context.Context, leaderboardID string, rank, limit int,
+	order string) ([]*Member, error) {
+	if rank < 0 {
+		rank = 0
+	}
+	if limit == 0 {
+		return []*Member{}, nil
+	}
+
+	startOffset := rank
+	endOffset := -1
+	if limit != -1 {
+		endOffset = startOffset + limit - 1
+	}
+	return getMembersByRange(c.redisWithTracing(ctx), leaderboardID, startOffset, endOffset, order)
+}
+
+// GetMembersAbsent returns which of the given member IDs are not in the leaderboard
+func (c *Client) GetMembersAbsent(ctx context.Context, leaderboardID string, memberIDs []string) ([]string, error) {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	scoreCmds := make([]*redis.FloatCmd, len(memberIDs))
+	for i, memberID := range memberIDs {
+		scoreCmds[i] = pipe.ZScore(leaderboardID, memberID)
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of member scores failed: %v", err)
+	}
+
+	absent := []string{}
+	for i, memberID := range memberIDs {
+		if scoreCmds[i].Err() == redis.Nil {
+			absent = append(absent, memberID)
+		}
+	}
+	return absent, nil
+}
+
+// GetAroundScoreRange returns the members with scores between minScore and maxScore, both included,
+// plus the member right outside each end of the interval. If that is less than pageSize members, the
+// page is expanded evenly on both ends.
+func (c *Client) GetAroundScoreRange(ctx context.Context, leaderboardID string, pageSize int, minScore, maxScore int64,
+	order string) ([]*Member, error) {
+	if minScore > maxScore {
+		return nil, fmt.Errorf("Min score %d must not be greater than max score %d", minScore, maxScore)
+	}
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	redisClient := c.redisWithTracing(ctx)
+
+	pipe := redisClient.TxPipeline()
+	totalCmd := pipe.ZCard(leaderboardID)
+	var aheadCmd, throughCmd *redis.IntCmd
+	if order == "desc" {
+		aheadCmd = pipe.ZCount(leaderboardID, "("+strconv.FormatInt(maxScore, 10), "+inf")
+		throughCmd = pipe.ZCount(leaderboardID, strconv.FormatInt(minScore, 10), "+inf")
+	} else {
+		aheadCmd = pipe.ZCount(leaderboardID, "-inf", "("+strconv.FormatInt(minScore, 10))
+		throughCmd = pipe.ZCount(leaderboardID, "-inf", strconv.FormatInt(maxScore, 10))
+	}
+	_, err := pipe.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("Counting members around score range failed: %v", err)
+	}
+
+	totalMembers := int(totalCmd.Val())
+	startOffset := int(aheadCmd.Val()) - 1
+	endOffset := int(throughCmd.Val())
+	if missing := pageSize - (endOffset - startOffset + 1); missing > 0 {
+		startOffset -= missing / 2
+		endOffset += missing - missing/2
+	}
+	if startOffset < 0 {
+		endOffset -= startOffset
+		startOffset = 0
+	}
+	if endOffset > totalMembers-1 {
+		startOffset -= endOffset - (totalMembers - 1)
+		endOffset = totalMembers - 1
+		if startOffset < 0 {
+			startOffset = 0
+		}
+	}
+	if endOffset < startOffset {
+		return []*Member{}, nil
+	}
+
+	return getMembersByRange(redisClient, leaderboardID, startOffset, endOffset, order)
+}
+
+// healthCheckPingTimeout is how long HealthCheck waits for Redis to answer a ping
+const healthCheckPingTimeout = time.Second
+
+// HealthCheckStatus is the outcome of a single check of HealthCheck
+type HealthCheckStatus struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// HealthCheckResult is the outcome of all checks of HealthCheck
+type HealthCheckResult struct {
+	Healthy bool                 `json:"healthy"`
+	Checks  []*HealthCheckStatus `json:"checks"`
+}
+
+func (r *HealthCheckResult) add(name string, err error) {
+	status := &HealthCheckStatus{Name: name, OK: err == nil}
+	if err != nil {
+		status.Error = err.Error()
+		r.Healthy = false
+	}
+	r.Checks = append(r.Checks, status)
+}
+
+// HealthCheck verifies Redis answers pings in time and that the keys of a leaderboard have the
+// expected types, reporting the outcome of each check
+func (c *Client) HealthCheck(ctx context.Context, leaderboardID string) *HealthCheckResult {
+	result := &HealthCheckResult{Healthy: true}
+	redisClient := c.redisWithTracing(ctx)
+
+	pingErr := make(chan error, 1)
+	go func() {
+		res, err := redisClient.Ping().Result()
+		if err == nil && res != "PONG" {
+			err = fmt.Errorf("Redis return = %s, want PONG", res)
+		}
+		pingErr <- err
+	}()
+	select {
+	case err := <-pingErr:
+		result.add("ping", err)
+	case <-time.After(healthCheckPingTimeout):
+		result.add("ping", fmt.Errorf("Redis did not answer ping in %v", healthCheckPingTimeout))
+		return result
+	}
+	if !result.Healthy {
+		return result
+	}
+
+	pipe := redisClient.TxPipeline()
+	existsCmd := pipe.Exists(leaderboardID)
+	ttlTypeCmd := pipe.Type(fmt.Sprintf("%s:ttl", leaderboardID))
+	_, err := pipe.Exec()
+	if err != nil {
+		result.add("keys", fmt.Errorf("Checking leaderboard keys failed: %v", err))
+		return result
+	}
+
+	if exists := existsCmd.Val(); exists != 0 && exists != 1 {
+		result.add("exists", fmt.Errorf("Unexpected EXISTS result %d", exists))
+	} else {
+		result.add("exists", nil)
+	}
+	if ttlType := ttlTypeCmd.Val(); ttlType != "zset" && ttlType != "none" {
+		result.add("ttl", fmt.Errorf("Unexpected type %s of score TTL key", ttlType))
+	} else {
+		result.add("ttl", nil)
+	}
+	return result
+}
+
+// ErrBufferTooSmall is returned by GetMembersIntoSlice when the given slice cannot hold a full page
+var ErrBufferTooSmall = fmt.Errorf("Buffer is smaller than page size")
+
+// GetMembersIntoSlice writes a page of members into dst and returns how many were written, so callers
+// can reuse buffers in hot paths. Members already in dst are overwritten in place instead of allocated.
+// Returns ErrBufferTooSmall if dst is shorter than pageSize.
+func (c *Client) GetMembersIntoSlice(ctx context.Context, leaderboardID string, dst Members, pageSize, page int,
+	order string) (int, error) {
+	if len(dst) < pageSize {
+		return 0, ErrBufferTooSmall
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	startOffset := int64((page - 1) * pageSize)
+	endOffset := startOffset + int64(pageSize) - 1
+	var values []redis.Z
+	var err error
+	if order == "asc" {
+		values, err = c.redisWithTracing(ctx).ZRangeWithScores(leaderboardID, startOffset, endOffset).Result()
+	} else {
+		values, err = c.redisWithTracing(ctx).ZRevRangeWithScores(leaderboardID, startOffset, endOffset).Result()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Retrieval of members for range (start %d end %d) failed: %v", startOffset, endOffset, err)
+	}
+
+	for i, value := range values {
+		if dst[i] == nil {
+			dst[i] = &Member{}
+		}
+		*dst[i] = Member{
+			PublicID: value.Member.(string),
+			Score:    int64(value.Score),
+			Rank:     int(startOffset) + i + 1,
+		}
+	}
+	return len(values), nil
+}
+
+// GetMemberCountByTTLBucket returns, for each of the given durations, how many member scores expire
+// between now and now plus that duration. A duration of 0 counts member scores already expired but
+// not yet removed by the expiration worker.
+func (c *Client) GetMemberCountByTTLBucket(ctx context.Context, leaderboardID string,
+	buckets []time.Duration) ([]int64, error) {
+	now := time.Now()
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	countCmds := make([]*redis.IntCmd, len(buckets))
+	for i, bucket := range buckets {
+		if bucket == 0 {
+			countCmds[i] = pipe.ZCount(fmt.Sprintf("%s:ttl", leaderboardID), "-inf", strconv.FormatInt(now.Unix(), 10))
+		} else {
+			countCmds[i] = pipe.ZCount(fmt.Sprintf("%s:ttl", leaderboardID), "("+strconv.FormatInt(now.Unix(), 10),
+				strconv.FormatInt(now.Add(bucket).Unix(), 10))
+		}
+	}
+	_, err := pipe.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("Counting members by TTL failed: %v", err)
+	}
+
+	counts := make([]int64, len(buckets))
+	for i, cmd := range countCmds {
+		counts[i] = cmd.Val()
+	}
+	return counts, nil
+}
+
+// HeatmapEnabled returns whether score updates are counted by hour of the day
+func (c *Client) HeatmapEnabled() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.heatmapEnabled
+}
+
+// SetHeatmapEnabled sets whether score updates are counted by hour of the day
+func (c *Client) SetHeatmapEnabled(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.heatmapEnabled = enabled
+}
+
+func heatmapKeys(leaderboardID string) []string {
+	keys := make([]string, 24)
+	for hour := range keys {
+		keys[hour] = fmt.Sprintf("%s:heatmap:%d", leaderboardID, hour)
+	}
+	return keys
+}
+
+// GetLeaderboardHeatmap returns how many member scores were updated in each hour of the day, in UTC,
+// while the heatmap was enabled
+func (c *Client) GetLeaderboardHeatmap(ctx context.Context, leaderboardID string) ([24]int64, error) {
+	var heatmap [24]int64
+	values, err := c.redisWithTracing(ctx).MGet(heatmapKeys(leaderboardID)...).Result()
+	if err != nil {
+		return heatmap, fmt.Errorf("Retrieval of leaderboard heatmap failed: %v", err)
+	}
+	for hour, value := range values {
+		if value != nil {
+			heatmap[hour], _ = strconv.ParseInt(value.(string), 10, 64)
+		}
+	}
+	return heatmap, nil
+}
+
+// ResetHeatmap sets the update count of every hour of the day back to zero
+func (c *Client) ResetHeatmap(ctx context.Context, leaderboardID string) error {
+	_, err := c.redisWithTracing(ctx).Del(heatmapKeys(leaderboardID)...).Result()
+	if err != nil {
+		return fmt.Errorf("Reset of leaderboard heatmap failed: %v", err)
+	}
+	return nil
+}
+
+// GetMembersUpdatedInWindow returns the members whose scores were updated within the given window
+// until now, sorted by rank. Updates are only kept with WithUpdateTracking, so the window must not be
+// longer than the tracking one.
+func (c *Client) GetMembersUpdatedInWindow(ctx context.Context, leaderboardID string, window time.Duration,
+	order string) ([]*Member, error) {
+	now := time.Now()
+	memberIDs, err := c.redisWithTracing(ctx).ZRangeByScore(leaderboardID+":updates", redis.ZRangeBy{
+		Min: strconv.FormatInt(now.Add(-window).Unix(), 10),
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of updated members failed: %v", err)
+	}
+	if len(memberIDs) == 0 {
+		return []*Member{}, nil
+	}
+
+	return c.GetMembers(ctx, leaderboardID, memberIDs, order, false, false)
+}
+
+// RankGroup is a range of ranks, from Start to End, both 1-based and included
+type RankGroup struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// GetRankDistribution returns how many members are ranked within each of the given rank groups
+func (c *Client) GetRankDistribution(ctx context.Context, leaderboardID string, rankGroups []RankGroup) ([]int64, error) {
+	totalMembers, err := c.totalMembers(c.redisWithTracing(ctx), leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]int64, len(rankGroups))
+	for i, group := range rankGroups {
+		start, end := group.Start, group.End
+		if start < 1 {
+			start = 1
+		}
+		if end > totalMembers {
+			end = totalMembers
+		}
+		if end >= start {
+			counts[i] = int64(end - start + 1)
+		}
+	}
+	return counts, nil
+}
+
+// PeakScoreEnabled returns whether the highest score of each member is kept
+func (c *Client) PeakScoreEnabled() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.peakScore
+}
+
+// SetPeakScoreEnabled sets whether the highest score of each member is kept
+func (c *Client) SetPeakScoreEnabled(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.peakScore = enabled
+}
+
+// GetMemberPeakScore returns the highest score a member ever had while peak scores were enabled
+func (c *Client) GetMemberPeakScore(ctx context.Context, leaderboardID string, memberID string) (int64, error) {
+	peak, err := c.redisWithTracing(ctx).HGet(fmt.Sprintf("%s:peak", leaderboardID), memberID).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, NewMemberNotFound(leaderboardID, memberID)
+		}
+		return 0, fmt.Errorf("Retrieval of member peak score failed: %v", err)
+	}
+	return peak, nil
+}
+
+// GetTopNByPeakScore returns the n members with the highest peak scores, with the peak score in the
+// Score field and ranks among peak scores. Members with the same peak score are ranked by public ID.
+// This reads every peak score, which is O(N).
+func (c *Client) GetTopNByPeakScore(ctx context.Context, leaderboardID string, n int) ([]*Member, error) {
+	peaks, err := c.redisWithTracing(ctx).HGetAll(fmt.Sprintf("%s:peak", leaderboardID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of peak scores failed: %v", err)
+	}
+
+	members := make([]*Member, 0, len(peaks))
+	for memberID, peak := range peaks {
+		score, _ := strconv.ParseInt(peak, 10, 64)
+		members = append(members, &Member{PublicID: memberID, Score: score})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Score == members[j].Score {
+			return members[i].PublicID < members[j].PublicID
+		}
+		return members[i].Score > members[j].Score
+	})
+	if n < len(members) {
+		members = members[:n]
+	}
+	for i, member := range members {
+		member.Rank = i + 1
+	}
+	return members, nil
+}
+
+// CompressLeaderboard collapses members with tied scores into a single member, returning how many
+// members were removed. Scores are compared as integers, and each group of tied members is replaced
+// by its first member in ascending order, whose score becomes the group's "average", rounded to the nearest
+// integer, "max" or, for "keep_first", stays unchanged. The data kept for the removed members, such as score
+// TTLs, update times, peak scores and streaks, is deleted as in RemoveMembers, while the all-time top scores are
+// kept. This cannot be undone, so callers should copy the leaderboard first.
+func (c *Client) CompressLeaderboard(ctx context.Context, leaderboardID string, strategy string) (int64, error) {
+	if strategy != "average" && strategy != "max" && strategy != "keep_first" {
+		return 0, fmt.Errorf("Invalid compression strategy %s, must be average, max or keep_first", strategy)
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the compression strategy
+		-- ARGV[2] is the json encoded list of member data set suffixes
+		-- ARGV[3] is the json encoded map of member data hash suffixes to their field prefixes
+
+		local sets = cjson.decode(ARGV[2])
+		local hashes = cjson.decode(ARGV[3])
+		local removed = 0
+		local values = redis.call("ZRANGE", KEYS[1], 0, -1, "WITHSCORES")
+
+		local function compress(group)
+			if #group < 2 then
+				return
+			end
+			local score = group[1][2]
+			if ARGV[1] ~= "keep_first" then
+				local sum = 0
+				for i, mem in ipairs(group) do
+					sum = sum + mem[2]
+					if mem[2] > score then
+						score = mem[2]
+					end
+				end
+				if ARGV[1] == "average" then
+					score = math.floor(sum / #group + 0.5)
+				end
+			end
+			redis.call("ZADD", KEYS[1], score, group[1][1])
+			for i = 2, #group do
+				local member_id = group[i][1]
+				redis.call("ZREM", KEYS[1], member_id)
+				for _, suffix in ipairs(sets) do
+					redis.call("ZREM", KEYS[1]..":"..suffix, member_id)
+				end
+				for suffix, prefixes in pairs(hashes) do
+					for _, prefix in ipairs(prefixes) do
+						redis.call("HDEL", KEYS[1]..":"..suffix, prefix..member_id)
+					end
+				end
+				removed = removed + 1
+			end
+		end
+
+		local group = {}
+		for i = 1, #values, 2 do
+			local score = tonumber(values[i + 1])
+			if #group > 0 and math.floor(group[1][2]) ~= math.floor(score) then
+				compress(group)
+				group = {}
+			end
+			table.insert(group, {values[i], score})
+		end
+		compress(group)
+		return removed
+	`)
+
+	sets, _ := json.Marshal(memberDataSets)
+	hashes, _ := json.Marshal(memberDataHashes)
+	removed, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, strategy, sets, hashes).Result()
+	if err != nil {
+		return 0, fmt.Errorf("Leaderboard compression failed: %v", err)
+	}
+	c.InvalidateCache(leaderboardID)
+	return removed.(int64), nil
+}
+
+// PageResult is a page of members along with its page number
+type PageResult struct {
+	Page    int       `json:"page"`
+	Members []*Member `json:"members"`
+}
+
+// GetPageContaining returns the page, as returned by GetLeaders, where the given member is.
+// Unlike GetAroundMe the member is not centred and may be anywhere in the page.
+func (c *Client) GetPageContaining(ctx context.Context, leaderboardID string, memberID string, pageSize int,
+	order string) (*PageResult, error) {
+	rank, err := c.GetRank(ctx, leaderboardID, memberID, order)
+	if err != nil {
+		return nil, err
+	}
+
+	page := (rank + pageSize - 1) / pageSize
+	members, err := c.GetLeaders(ctx, leaderboardID, pageSize, page, order, false)
+	if err != nil {
+		return nil, err
+	}
+	return &PageResult{Page: page, Members: members}, nil
+}
+
+// scoreHistoryMaxEntries is how many score history entries are kept for each member
+const scoreHistoryMaxEntries = 1000
+
+// ErrNoHistoryBeforeTimestamp is returned by GetMemberScoreAt when a member had no score at the given time
+var ErrNoHistoryBeforeTimestamp = fmt.Errorf("No score history before timestamp")
+
+// ScoreHistoryEnabled returns whether the score history of each member is kept
+func (c *Client) ScoreHistoryEnabled() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.scoreHistory
+}
+
+// SetScoreHistoryEnabled sets whether the score history of each member is kept
+func (c *Client) SetScoreHistoryEnabled(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.scoreHistory = enabled
+}
+
+// GetMemberScoreAt returns the score a member had at the given time, according to its score history.
+// Returns ErrNoHistoryBeforeTimestamp if the member score history has no entry up to that time.
+func (c *Client) GetMemberScoreAt(ctx context.Context, leaderboardID string, memberID string,
+	at time.Time) (int64, error) {
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the score history of the member
+		-- ARGV[1] is the unix timestamp
+
+		-- binary search for the last entry up to the timestamp
+		local at = tonumber(ARGV[1])
+		local low = 0
+		local high = redis.call("LLEN", KEYS[1]) - 1
+		local found = nil
+		while low <= high do
+			local middle = math.floor((low + high) / 2)
+			local entry = cjson.decode(redis.call("LINDEX", KEYS[1], middle))
+			if entry["timestamp"] <= at then
+				found = entry
+				low = middle + 1
+			else
+				high = middle - 1
+			end
+		end
+		if not found then
+			return false
+		end
+		return string.format("%.0f", found["score"])
+	`)
+
+	score, err := script.Run(c.redisWithTracing(ctx), []string{fmt.Sprintf("%s:history:%s", leaderboardID, memberID)},
+		at.Unix()).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, ErrNoHistoryBeforeTimestamp
+		}
+		return 0, fmt.Errorf("Retrieval of member score history failed: %v", err)
+	}
+	return strconv.ParseInt(score.(string), 10, 64)
+}
+
+// TierResult is a tier of members as returned by GetTopTiers
+type TierResult struct {
+	TierIndex int       `json:"tierIndex"`
+	Members   []*Member `json:"members"`
+	MinScore  int64     `json:"minScore"`
+	MaxScore  int64     `json:"maxScore"`
+}
+
+// GetTopTiers splits the top members into consecutive tiers, each one with the given percentage of
+// the members, e.g. 1, 9 and 40 for the top 1%, the next 9% and the next 40%. Tier sizes are rounded
+// up, and members past the sum of the percentages are not returned.
+func (c *Client) GetTopTiers(ctx context.Context, leaderboardID string, tierPercentages []float64,
+	order string) ([]TierResult, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	var operations = map[string]string{
+		"range_desc": "ZREVRANGE",
+		"range_asc":  "ZRANGE",
+	}
+
+	var totalPercentage float64
+	for _, percentage := range tierPercentages {
+		if percentage < 0 {
+			return nil, fmt.Errorf("Tier percentages must not be negative")
+		}
+		totalPercentage += percentage
+	}
+	if totalPercentage > 100 {
+		return nil, fmt.Errorf("Tier percentages must not add up to more than 100")
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the percentage of members in all tiers
+
+		local total = redis.call("ZCARD", KEYS[1])
+		local last = math.ceil(total * tonumber(ARGV[1]) / 100) - 1
+		if last < 0 then
+			return {total, {}}
+		end
+		return {total, redis.call("` + operations["range_"+order] + `", KEYS[1], 0, last, "WITHSCORES")}
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID},
+		strconv.FormatFloat(totalPercentage, 'f', -1, 64)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of top tiers failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	totalMembers := float64(res[0].(int64))
+	members := membersFromScoreList(res[1].([]interface{}), 1)
+
+	tiers := make([]TierResult, len(tierPercentages))
+	start := 0
+	var cumulativePercentage float64
+	for i, percentage := range tierPercentages {
+		cumulativePercentage += percentage
+		end := int(math.Ceil(totalMembers * cumulativePercentage / 100))
+		if end > len(members) {
+			end = len(members)
+		}
+		if end < start {
+			end = start
+		}
+		tiers[i] = TierResult{TierIndex: i, Members: members[start:end]}
+		if end > start {
+			tiers[i].MinScore, tiers[i].MaxScore = members[end-1].Score, members[start].Score
+			if order == "asc" {
+				tiers[i].MinScore, tiers[i].MaxScore = members[start].Score, members[end-1].Score
+			}
+		}
+		start = end
+	}
+	return tiers, nil
+}
+
+// RankProgress is how far a member is from reaching the next rank, as returned by GetProgressToNextRank
+type RankProgress struct {
+	CurrentScore      int64   `json:"currentScore"`
+	NextRankScore     int64   `json:"nextRankScore"`
+	PreviousRankScore int64   `json:"previousRankScore"`
+	Fraction          float64 `json:"fraction"`
+}
+
+// GetProgressToNextRank returns the scores of a member and of its neighbours, and the fraction of the
+// way the member made from the score of the member ranked below it to the one ranked above it.
+// The first member has a fraction of 1 and the last member uses its own score as the previous one.
+func (c *Client) GetProgressToNextRank(ctx context.Context, leaderboardID string, memberID string,
+	order string) (*RankProgress, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	var operations = map[string]string{
+		"range_desc": "ZREVRANGE",
+		"rank_desc":  "ZREVRANK",
+		"range_asc":  "ZRANGE",
+		"rank_asc":   "ZRANK",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the member's public ID
+
+		local rank = redis.call("` + operations["rank_"+order] + `", KEYS[1], ARGV[1])
+		if not rank then
+			return false
+		end
+		local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+		local next_score = score
+		if rank > 0 then
+			next_score = redis.call("` + operations["range_"+order] + `", KEYS[1], rank - 1, rank - 1, "WITHSCORES")[2]
+		end
+		local previous_score = redis.call("` + operations["range_"+order] + `", KEYS[1], rank + 1, rank + 1, "WITHSCORES")[2] or score
+		return {score, next_score, previous_score}
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, memberID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, NewMemberNotFound(leaderboardID, memberID)
+		}
+		return nil, fmt.Errorf("Retrieval of progress to next rank failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	progress := &RankProgress{Fraction: 1.0}
+	progress.CurrentScore, _ = strconv.ParseInt(res[0].(string), 10, 64)
+	progress.NextRankScore, _ = strconv.ParseInt(res[1].(string), 10, 64)
+	progress.PreviousRankScore, _ = strconv.ParseInt(res[2].(string), 10, 64)
+	if progress.NextRankScore != progress.PreviousRankScore {
+		progress.Fraction = float64(progress.CurrentScore-progress.PreviousRankScore) /
+			float64(progress.NextRankScore-progress.PreviousRankScore)
+	}
+	return progress, nil
+}
+
+// GrowthPoint is the number of members that joined a leaderboard up to a point in time
+type GrowthPoint struct {
+	Timestamp int64 `json:"timestamp"`
+	Total     int64 `json:"total"`
+}
+
+// JoinTrackingEnabled returns whether the time each member joined a leaderboard is recorded
+func (c *Client) JoinTrackingEnabled() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.joinTracking
+}
+
+// SetJoinTrackingEnabled sets whether the time each member joined a leaderboard is recorded
+func (c *Client) SetJoinTrackingEnabled(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.joinTracking = enabled
+}
+
+// GetGrowthRate returns how many members joined the leaderboard within the given window until now.
+// Join times are only recorded with WithJoinTracking.
+func (c *Client) GetGrowthRate(ctx context.Context, leaderboardID string, window time.Duration) (int64, error) {
+	now := time.Now()
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	cmd := pipe.ZCount(fmt.Sprintf("%s:joined", leaderboardID), "("+strconv.FormatInt(now.Add(-window).Unix(), 10),
+		strconv.FormatInt(now.Unix(), 10))
+	_, err := pipe.Exec()
+	if err != nil {
+		return 0, fmt.Errorf("Retrieval of leaderboard growth failed: %v", err)
+	}
+	return cmd.Val(), nil
+}
+
+// GetCumulativeGrowth returns, for each time members joined the leaderboard, how many members had
+// joined up to then. Members removed from the leaderboard are still counted. Join times are only recorded
+// with WithJoinTracking.
+func (c *Client) GetCumulativeGrowth(ctx context.Context, leaderboardID string) ([]GrowthPoint, error) {
+	joined, err := c.redisWithTracing(ctx).ZRangeWithScores(fmt.Sprintf("%s:joined", leaderboardID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of leaderboard growth failed: %v", err)
+	}
+
+	points := []GrowthPoint{}
+	for i, join := range joined {
+		timestamp := int64(join.Score)
+		if len(points) > 0 && points[len(points)-1].Timestamp == timestamp {
+			points[len(points)-1].Total = int64(i + 1)
+			continue
+		}
+		points = append(points, GrowthPoint{Timestamp: timestamp, Total: int64(i + 1)})
+	}
+	return points, nil
+}
+
+// cohortBatchSize is how many members of a cohort have their scores fetched in each pipeline
+const cohortBatchSize = 500
+
+// GetMembersForCohort returns a page of the members that joined the leaderboard between cohortStart, included,
+// and cohortEnd, excluded, sorted by rank in the given order. Ranks are the ranks in the whole leaderboard,
+// and members of the cohort removed from the leaderboard are left out. Join times are only recorded with
+// WithJoinTracking.
+func (c *Client) GetMembersForCohort(ctx context.Context, leaderboardID string, cohortStart, cohortEnd time.Time,
+	pageSize, page int, order string) ([]*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	if page < 1 {
+		page = 1
+	}
+	redisClient := c.redisWithTracing(ctx)
+
+	memberIDs, err := redisClient.ZRangeByScore(fmt.Sprintf("%s:joined", leaderboardID), redis.ZRangeBy{
+		Min: strconv.FormatInt(cohortStart.Unix(), 10),
+		Max: "(" + strconv.FormatInt(cohortEnd.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of cohort members failed: %v", err)
+	}
+
+	members := make([]*Member, 0, len(memberIDs))
+	for start := 0; start < len(memberIDs); start += cohortBatchSize {
+		end := start + cohortBatchSize
+		if end > len(memberIDs) {
+			end = len(memberIDs)
+		}
+
+		pipe := redisClient.TxPipeline()
+		scoreCmds := make([]*redis.FloatCmd, end-start)
+		rankCmds := make([]*redis.IntCmd, end-start)
+		for i, memberID := range memberIDs[start:end] {
+			scoreCmds[i] = pipe.ZScore(leaderboardID, memberID)
+			if order == "desc" {
+				rankCmds[i] = pipe.ZRevRank(leaderboardID, memberID)
+			} else {
+				rankCmds[i] = pipe.ZRank(leaderboardID, memberID)
+			}
+		}
+		_, err = pipe.Exec()
+		if err != nil && err != redis.Nil {
+			return nil, fmt.Errorf("Retrieval of cohort members scores failed: %v", err)
+		}
+
+		for i, memberID := range memberIDs[start:end] {
+			if scoreCmds[i].Err() != nil || rankCmds[i].Err() != nil {
+				continue
+			}
+			members = append(members, &Member{
+				PublicID: memberID,
+				Score:    int64(scoreCmds[i].Val()),
+				Rank:     int(rankCmds[i].Val()) + 1,
+			})
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Rank < members[j].Rank
+	})
+	startOffset := (page - 1) * pageSize
+	if startOffset >= len(members) {
+		return []*Member{}, nil
+	}
+	endOffset := startOffset + pageSize
+	if endOffset > len(members) {
+		endOffset = len(members)
+	}
+	return members[startOffset:endOffset], nil
+}
+
+// GetMembersInAllLeaderboards returns the members present in every one of the given leaderboards, with
+// their scores and ranks in the first one, sorted by rank
+func (c *Client) GetMembersInAllLeaderboards(ctx context.Context, leaderboardIDs []string,
+	order string) ([]*Member, error) {
+	if len(leaderboardIDs) == 0 {
+		return nil, fmt.Errorf("At least one leaderboard must be provided.")
+	}
+
+	weights := make([]float64, len(leaderboardIDs))
+	for i := range weights {
+		weights[i] = 1
+	}
+	interKey := fmt.Sprintf("inter:%s", uuid.NewV4().String())
+
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	pipe.ZInterStore(interKey, redis.ZStore{Weights: weights, Aggregate: "MIN"}, leaderboardIDs...)
+	memberIDsCmd := pipe.ZRange(interKey, 0, -1)
+	pipe.Del(interKey)
+	_, err := pipe.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("Intersection of leaderboards failed: %v", err)
+	}
+	if len(memberIDsCmd.Val()) == 0 {
+		return []*Member{}, nil
+	}
+
+	return c.GetMembers(ctx, leaderboardIDs[0], memberIDsCmd.Val(), order, false, false)
+}
+
+// BulkIncrementMembersScore atomically increments the scores of the members with the given IDs by
+// their Score fields, and returns the members with their new scores and ranks
+func (c *Client) BulkIncrementMembersScore(ctx context.Context, leaderboardID string, members Members,
+	scoreTTL string) (Members, error) {
+	return c.setMembersScore(ctx, leaderboardID, members, scoreTTL, true, setScoreScriptOptions{})
+}
+
+// MatchResult is the outcome of a match between two members
+type MatchResult struct {
+	WinnerID    string `json:"winnerID"`
+	LoserID     string `json:"loserID"`
+	WinnerDelta int64  `json:"winnerDelta"`
+	LoserDelta  int64  `json:"loserDelta"`
+}
+
+// MatchOutcome has both members of a match after their scores were updated
+type MatchOutcome struct {
+	Winner *Member `json:"winner"`
+	Loser  *Member `json:"loser"`
+}
+
+// RecordMatchResult atomically applies the score changes of a match to the winner and the loser
+func (c *Client) RecordMatchResult(ctx context.Context, leaderboardID string, result MatchResult,
+	scoreTTL string) (*MatchOutcome, error) {
+	if result.WinnerDelta < 0 {
+		return nil, fmt.Errorf("Winner delta must not be negative")
+	}
+	if result.LoserDelta > 0 {
+		return nil, fmt.Errorf("Loser delta must not be positive")
+	}
+	if result.WinnerID == result.LoserID {
+		return nil, fmt.Errorf("Winner and loser must be different members")
+	}
+
+	members, err := c.BulkIncrementMembersScore(ctx, leaderboardID, Members{
+		&Member{PublicID: result.WinnerID, Score: result.WinnerDelta},
+		&Member{PublicID: result.LoserID, Score: result.LoserDelta},
+	}, scoreTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &MatchOutcome{Winner: members[0], Loser: members[1]}, nil
+}
+
+// TagMember adds a member to the given tag groups of a leaderboard
+func (c *Client) TagMember(ctx context.Context, leaderboardID string, memberID string, tags ...string) error {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	for _, tag := range tags {
+		pipe.SAdd(fmt.Sprintf("%s:tagindex:%s", leaderboardID, tag), memberID)
+	}
+	_, err := pipe.Exec()
+	if err != nil {
+		return fmt.Errorf("Tagging member failed: %v", err)
+	}
+	return nil
+}
+
+// UntagMember removes a member from the given tag groups of a leaderboard
+func (c *Client) UntagMember(ctx context.Context, leaderboardID string, memberID string, tags ...string) error {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	for _, tag := range tags {
+		pipe.SRem(fmt.Sprintf("%s:tagindex:%s", leaderboardID, tag), memberID)
+	}
+	_, err := pipe.Exec()
+	if err != nil {
+		return fmt.Errorf("Untagging member failed: %v", err)
+	}
+	return nil
+}
+
+// GetTopScorerByTag returns the best ranked member with the given tag, or nil if no member with the tag
+// is in the leaderboard
+func (c *Client) GetTopScorerByTag(ctx context.Context, leaderboardID string, tag string, order string) (*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	var operations = map[string]string{
+		"rank_desc": "ZREVRANK",
+		"rank_asc":  "ZRANK",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- KEYS[2] is the set of members with the tag
+
+		local best_id = nil
+		local best_rank = nil
+		for i, member_id in ipairs(redis.call("SMEMBERS", KEYS[2])) do
+			local rank = redis.call("` + operations["rank_"+order] + `", KEYS[1], member_id)
+			if rank and (best_rank == nil or rank < best_rank) then
+				best_id = member_id
+				best_rank = rank
+			end
+		end
+		if best_id == nil then
+			return false
+		end
+		return {best_id, best_rank, redis.call("ZSCORE", KEYS[1], best_id)}
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID,
+		fmt.Sprintf("%s:tagindex:%s", leaderboardID, tag)}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Retrieval of top scorer by tag failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	score, _ := strconv.ParseInt(res[2].(string), 10, 64)
+	return &Member{PublicID: res[0].(string), Score: score, Rank: int(res[1].(int64)) + 1}, nil
+}
+
+// GetMembersWithExpiredTTL returns the members whose score already expired but were not removed yet,
+// along with their rank and expiration time
+func (c *Client) GetMembersWithExpiredTTL(ctx context.Context, leaderboardID string, order string) ([]*Member, error) {
+	memberIDs, err := c.redisWithTracing(ctx).ZRangeByScore(leaderboardID+":ttl", redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of expired members failed: %v", err)
+	}
+	if len(memberIDs) == 0 {
+		return []*Member{}, nil
+	}
+
+	return c.GetMembers(ctx, leaderboardID, memberIDs, order, true, false)
+}
+
+// StreakEnabled returns whether consecutive score improvements of each member are counted
+func (c *Client) StreakEnabled() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.streak
+}
+
+// SetStreakEnabled sets whether consecutive score improvements of each member are counted
+func (c *Client) SetStreakEnabled(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.streak = enabled
+}
+
+// StreakEntry is the number of consecutive score improvements of a member
+type StreakEntry struct {
+	PublicID string `json:"publicID"`
+	Streak   int64  `json:"streak"`
+}
+
+// GetMembersWithHighestStreak returns the n members with the longest running streaks of score
+// improvements. A streak grows each time a member's score is set higher than it was and is reset
+// by any other update. Members with the same streak are sorted by public ID.
+func (c *Client) GetMembersWithHighestStreak(ctx context.Context, leaderboardID string, n int) ([]StreakEntry, error) {
+	streaks, err := c.redisWithTracing(ctx).HGetAll(fmt.Sprintf("%s:streak", leaderboardID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of member streaks failed: %v", err)
+	}
+
+	entries := make([]StreakEntry, 0, len(streaks))
+	for memberID, value := range streaks {
+		streak, _ := strconv.ParseInt(value, 10, 64)
+		entries = append(entries, StreakEntry{PublicID: memberID, Streak: streak})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Streak == entries[j].Streak {
+			return entries[i].PublicID < entries[j].PublicID
+		}
+		return entries[i].Streak > entries[j].Streak
+	})
+	if n < len(entries) {
+		entries = entries[:n]
+	}
+	return entries, nil
+}
+
+// moversScanBatchSize is the number of rank snapshot entries scanned at a time by scanRankChanges
+const moversScanBatchSize = 500
+
+// SnapshotMemberRanks stores the current descending ranks of the members with the given IDs, to be
+// compared later by GetTopMovers. Members that are not in the leaderboard are removed from the snapshot.
+func (c *Client) SnapshotMemberRanks(ctx context.Context, leaderboardID string, memberIDs []string) error {
+	if len(memberIDs) == 0 {
+		return nil
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- KEYS[2] is the name of the ranks snapshot
+		-- ARGV are the members' public IDs
+
+		for i, member in ipairs(ARGV) do
+			local rank = redis.call("ZREVRANK", KEYS[1], member)
+			if rank then
+				redis.call("HSET", KEYS[2], member, rank + 1)
+			else
+				redis.call("HDEL", KEYS[2], member)
+			end
+		end
+		return #ARGV
+	`)
+
+	args := make([]interface{}, len(memberIDs))
+	for i, memberID := range memberIDs {
+		args[i] = memberID
+	}
+	snapshotKey := fmt.Sprintf("%s:ranksnap", leaderboardID)
+	_, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID, snapshotKey}, args...).Result()
+	if err != nil {
+		return fmt.Errorf("Recording ranks snapshot failed: %v", err)
+	}
+	return nil
+}
+
+// MoverEntry is a member whose rank changed since the last ranks snapshot. Delta is positive if the
+// member climbed and negative if it dropped.
+type MoverEntry struct {
+	Member  *Member `json:"member"`
+	OldRank int     `json:"oldRank"`
+	Delta   int     `json:"delta"`
+}
+
+// GetTopMovers returns the n members that climbed ("up") or dropped ("down") the most ranks since their
+// ranks were recorded by SnapshotMemberRanks. The snapshot is scanned in batches, so only n entries are
+// kept in memory. Members no longer in the leaderboard are ignored.
+func (c *Client) GetTopMovers(ctx context.Context, leaderboardID string, n int, direction string) ([]*MoverEntry, error) {
+	if direction != "up" && direction != "down" {
+		return nil, fmt.Errorf("Invalid direction %s, must be up or down", direction)
+	}
+	if n <= 0 {
+		return []*MoverEntry{}, nil
+	}
+
+	magnitude := func(entry *MoverEntry) int {
+		if direction == "down" {
+			return -entry.Delta
+		}
+		return entry.Delta
+	}
+	movers := make([]*MoverEntry, 0, n+1)
+
+	err := c.scanRankChanges(ctx, leaderboardID, func(entry *MoverEntry) {
+		if magnitude(entry) <= 0 {
+			return
+		}
+
+		position := sort.Search(len(movers), func(j int) bool {
+			if magnitude(movers[j]) == magnitude(entry) {
+				return movers[j].Member.PublicID > entry.Member.PublicID
+			}
+			return magnitude(movers[j]) < magnitude(entry)
+		})
+		if position >= n {
+			return
+		}
+		movers = append(movers, nil)
+		copy(movers[position+1:], movers[position:])
+		movers[position] = entry
+		if len(movers) > n {
+			movers = movers[:n]
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movers, nil
+}
+
+// GetMembersWithRankChangeLargerThan returns the members whose rank changed by more than delta positions, in
+// either direction, since their ranks were recorded by SnapshotMemberRanks, sorted by their current rank.
+// Snapshots only hold desc ranks, so order must be desc or empty. The snapshot is scanned in batches and only
+// the members over the threshold are kept.
+func (c *Client) GetMembersWithRankChangeLargerThan(ctx context.Context, leaderboardID string, delta int,
+	order string) ([]*MoverEntry, error) {
+	if order != "" && order != "desc" {
+		return nil, fmt.Errorf("Invalid order %s, rank snapshots only hold desc ranks", order)
+	}
+
+	changed := []*MoverEntry{}
+	err := c.scanRankChanges(ctx, leaderboardID, func(entry *MoverEntry) {
+		if entry.Delta > delta || -entry.Delta > delta {
+			changed = append(changed, entry)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].Member.Rank < changed[j].Member.Rank
+	})
+	return changed, nil
+}
+
+// scanRankChanges scans the ranks snapshot recorded by SnapshotMemberRanks in batches, calling found with
+// each member that is still in the leaderboard along with its rank change
+func (c *Client) scanRankChanges(ctx context.Context, leaderboardID string, found func(entry *MoverEntry)) error {
+	cli := c.redisWithTracing(ctx)
+	snapshotKey := fmt.Sprintf("%s:ranksnap", leaderboardID)
+
+	var cursor uint64
+	for {
+		pipe := cli.TxPipeline()
+		scanCmd := pipe.HScan(snapshotKey, cursor, "", moversScanBatchSize)
+		_, err := pipe.Exec()
+		if err != nil {
+			return fmt.Errorf("Scanning ranks snapshot failed: %v", err)
+		}
+
+		values, nextCursor := scanCmd.Val()
+		if len(values) > 0 {
+			pipe = cli.TxPipeline()
+			rankCmds := make([]*redis.IntCmd, 0, len(values)/2)
+			scoreCmds := make([]*redis.FloatCmd, 0, len(values)/2)
+			for i := 0; i+1 < len(values); i += 2 {
+				rankCmds = append(rankCmds, pipe.ZRevRank(leaderboardID, values[i]))
+				scoreCmds = append(scoreCmds, pipe.ZScore(leaderboardID, values[i]))
+			}
+			_, err = pipe.Exec()
+			if err != nil && err != redis.Nil {
+				return fmt.Errorf("Retrieval of members rank failed: %v", err)
+			}
+
+			for i := range rankCmds {
+				if rankCmds[i].Err() != nil || scoreCmds[i].Err() != nil {
+					continue
+				}
+				oldRank, _ := strconv.Atoi(values[2*i+1])
+				rank := int(rankCmds[i].Val()) + 1
+				found(&MoverEntry{
+					Member:  &Member{PublicID: values[2*i], Score: int64(scoreCmds[i].Val()), Rank: rank},
+					OldRank: oldRank,
+					Delta:   oldRank - rank,
+				})
+			}
+		}
+
+		cursor = nextCursor
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// GetMemberCountAbovePercentile returns how many members are in the given top percentile of the
+// leaderboard. Members tied with the last member of the percentile are counted too.
+func (c *Client) GetMemberCountAbovePercentile(ctx context.Context, leaderboardID string, percentile float64) (int64, error) {
+	if percentile <= 0 || percentile > 100 {
+		return 0, fmt.Errorf("Percentile must be greater than 0 and at most 100")
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the percentile
+
+		local total = redis.call("ZCARD", KEYS[1])
+		if total == 0 then
+			return 0
+		end
+		local threshold_rank = math.ceil(total * tonumber(ARGV[1]) / 100)
+		local threshold = redis.call("ZREVRANGE", KEYS[1], threshold_rank - 1, threshold_rank - 1, "WITHSCORES")
+		return redis.call("ZCOUNT", KEYS[1], threshold[2], "+inf")
+	`)
+
+	count, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID},
+		strconv.FormatFloat(percentile, 'f', -1, 64)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("Counting members above percentile failed: %v", err)
+	}
+	return count.(int64), nil
+}
+
+// ErrEmptyLeaderboard is returned by GetScoreAtRankPercentile when the leaderboard has no members
+var ErrEmptyLeaderboard = fmt.Errorf("Leaderboard has no members")
+
+// GetScoreAtRankPercentile returns the score of the member at the given percentile of the ranking, that is
+// the member with the 0-based descending rank floor(percentile * totalMembers / 100), so that the given
+// percentage of the members is ranked above it. Returns ErrEmptyLeaderboard if the leaderboard has no members.
+func (c *Client) GetScoreAtRankPercentile(ctx context.Context, leaderboardID string, percentile float64) (int64, error) {
+	if percentile < 0 || percentile > 100 {
+		return 0, fmt.Errorf("Percentile must be between 0 and 100")
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the percentile
+
+		local total = redis.call("ZCARD", KEYS[1])
+		if total == 0 then
+			return false
+		end
+		local index = math.min(math.floor(tonumber(ARGV[1]) * total / 100), total - 1)
+		local res = redis.call("ZREVRANGE", KEYS[1], index, index, "WITHSCORES")
+		return res[2]
+	`)
+
+	score, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID},
+		strconv.FormatFloat(percentile, 'f', -1, 64)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, ErrEmptyLeaderboard
+		}
+		return 0, fmt.Errorf("Retrieval of score at percentile failed: %v", err)
+	}
+	value, err := strconv.ParseFloat(score.(string), 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(value), nil
+}
+
+// GetP50Score returns the median score of the leaderboard, see GetScoreAtRankPercentile
+func (c *Client) GetP50Score(ctx context.Context, leaderboardID string) (int64, error) {
+	return c.GetScoreAtRankPercentile(ctx, leaderboardID, 50)
+}
+
+// GetP75Score returns the score at the 75th percentile of the ranking, see GetScoreAtRankPercentile
+func (c *Client) GetP75Score(ctx context.Context, leaderboardID string) (int64, error) {
+	return c.GetScoreAtRankPercentile(ctx, leaderboardID, 75)
+}
+
+// GetP95Score returns the score at the 95th percentile of the ranking, see GetScoreAtRankPercentile
+func (c *Client) GetP95Score(ctx context.Context, leaderboardID string) (int64, error) {
+	return c.GetScoreAtRankPercentile(ctx, leaderboardID, 95)
+}
+
+// GetP99Score returns the score at the 99th percentile of the ranking, see GetScoreAtRankPercentile
+func (c *Client) GetP99Score(ctx context.Context, leaderboardID string) (int64, error) {
+	return c.GetScoreAtRankPercentile(ctx, leaderboardID, 99)
+}
+
+// GetMembersByScoreRange returns up to limit members with scores between minScore and maxScore, both included,
+// skipping the first offset ones, with their ranks in the given order. A limit of zero or less returns
+// all members in the range. Everything is resolved in a single round trip by a Lua script, which keeps
+// redis busy for the whole lookup, see GetMembersByScorePipeline for an alternative.
+func (c *Client) GetMembersByScoreRange(ctx context.Context, leaderboardID string, minScore, maxScore int64, limit int64,
+	offset int64, order string) ([]*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+
+	var operations = map[string]string{
+		"range_desc": "ZREVRANGEBYSCORE",
+		"rank_desc":  "ZREVRANK",
+		"range_asc":  "ZRANGEBYSCORE",
+		"rank_asc":   "ZRANK",
+	}
+	first, last := minScore, maxScore
+	if order == "desc" {
+		first, last = maxScore, minScore
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the first score of the range in the given order
+		-- ARGV[2] is the last score of the range in the given order
+		-- ARGV[3] is the offset
+		-- ARGV[4] is the limit
+
+		local values = redis.call("` + operations["range_"+order] + `", KEYS[1], ARGV[1], ARGV[2], "WITHSCORES",
+			"LIMIT", ARGV[3], ARGV[4])
+		local result = {}
+		for i = 1, #values, 2 do
+			table.insert(result, values[i])
+			table.insert(result, values[i + 1])
+			table.insert(result, redis.call("` + operations["rank_"+order] + `", KEYS[1], values[i]) + 1)
+		end
+		return result
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, first, last, offset, limit).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of members by score range failed: %v", err)
+	}
+
+	values := result.([]interface{})
+	members := make([]*Member, 0, len(values)/3)
+	for i := 0; i+2 < len(values); i += 3 {
+		score, _ := strconv.ParseFloat(values[i+1].(string), 64)
+		members = append(members, &Member{
+			PublicID: values[i].(string),
+			Score:    int64(score),
+			Rank:     int(values[i+2].(int64)),
+		})
+	}
+	return members, nil
+}
+
+// GetMembersByScorePipeline returns the same members as GetMembersByScoreRange using plain redis commands
+// instead of a Lua script: the members are fetched first and their ranks are then resolved in a single
+// pipeline. It takes two round trips instead of one, and members updated between them may be ranked
+// after the update, but redis is never blocked by a long script, which is preferable for large ranges.
+func (c *Client) GetMembersByScorePipeline(ctx context.Context, leaderboardID string, minScore, maxScore int64, limit int64,
+	offset int64, order string) ([]*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+
+	cli := c.redisWithTracing(ctx)
+	rangeBy := redis.ZRangeBy{
+		Min:    strconv.FormatInt(minScore, 10),
+		Max:    strconv.FormatInt(maxScore, 10),
+		Offset: offset,
+		Count:  limit,
+	}
+	var values []redis.Z
+	var err error
+	if order == "desc" {
+		values, err = cli.ZRevRangeByScoreWithScores(leaderboardID, rangeBy).Result()
+	} else {
+		values, err = cli.ZRangeByScoreWithScores(leaderboardID, rangeBy).Result()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of members by score range failed: %v", err)
+	}
+	if len(values) == 0 {
+		return []*Member{}, nil
+	}
+
+	pipe := cli.TxPipeline()
+	rankCmds := make([]*redis.IntCmd, len(values))
+	for i, value := range values {
+		if order == "desc" {
+			rankCmds[i] = pipe.ZRevRank(leaderboardID, value.Member.(string))
+		} else {
+			rankCmds[i] = pipe.ZRank(leaderboardID, value.Member.(string))
+		}
+	}
+	_, err = pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of members rank failed: %v", err)
+	}
+
+	members := make([]*Member, 0, len(values))
+	for i, value := range values {
+		// members removed between both round trips are skipped
+		if rankCmds[i].Err() != nil {
+			continue
+		}
+		members = append(members, &Member{
+			PublicID: value.Member.(string),
+			Score:    int64(value.Score),
+			Rank:     int(rankCmds[i].Val()) + 1,
+		})
+	}
+	return members, nil
+}
+
+// League is a named tier of the leaderboard holding members with scores between MinScore and MaxScore,
+// both included
+type League struct {
+	Name     string `json:"name"`
+	MinScore int64  `json:"minScore"`
+	MaxScore int64  `json:"maxScore"`
+}
+
+// ErrMemberNotInLeague is returned when a member score does not fall into any registered league
+var ErrMemberNotInLeague = fmt.Errorf("Member score is not in any league")
+
+// RegisterLeagues replaces the leagues of the leaderboard with the given ones. The score ranges of the
+// leagues must not overlap, so every score falls into at most one league.
+func (c *Client) RegisterLeagues(ctx context.Context, leaderboardID string, leagues []League) error {
+	fields := make(map[string]interface{}, len(leagues))
+	for _, league := range leagues {
+		if league.Name == "" {
+			return fmt.Errorf("League name must not be empty")
+		}
+		if league.MinScore > league.MaxScore {
+			return fmt.Errorf("League %s min score must not be greater than its max score", league.Name)
+		}
+		if _, ok := fields[league.Name]; ok {
+			return fmt.Errorf("League %s registered more than once", league.Name)
+		}
+		value, _ := json.Marshal(league)
+		fields[league.Name] = string(value)
+	}
+
+	sorted := make([]League, len(leagues))
+	copy(sorted, leagues)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinScore < sorted[j].MinScore })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].MinScore <= sorted[i-1].MaxScore {
+			return fmt.Errorf("League %s overlaps league %s", sorted[i].Name, sorted[i-1].Name)
+		}
+	}
+
+	leaguesKey := fmt.Sprintf("%s:leagues", leaderboardID)
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	pipe.Del(leaguesKey)
+	if len(fields) > 0 {
+		pipe.HMSet(leaguesKey, fields)
+	}
+	_, err := pipe.Exec()
+	if err != nil {
+		return fmt.Errorf("Registering leagues failed: %v", err)
+	}
+	return nil
+}
+
+// GetMemberLeague returns the league the member with the given ID is in and its rank within the league.
+// Returns ErrMemberNotInLeague if the member score is not in any registered league.
+func (c *Client) GetMemberLeague(ctx context.Context, leaderboardID string, memberID string) (*League, int, error) {
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- KEYS[2] is the name of the leaderboard's leagues
+		-- ARGV[1] is member's public ID
+
+		local score = tonumber(redis.call("ZSCORE", KEYS[1], ARGV[1]))
+		if not score then
+			return false
+		end
+
+		local leagues = redis.call("HVALS", KEYS[2])
+		for i, value in ipairs(leagues) do
+			local league = cjson.decode(value)
+			if score >= league["minScore"] and score <= league["maxScore"] then
+				-- concatenating the number would format it with %.14g, rounding scores of 15 digits or more
+				local above = redis.call("ZCOUNT", KEYS[1], "(" .. string.format("%.0f", league["maxScore"]), "+inf")
+				local rank = redis.call("ZREVRANK", KEYS[1], ARGV[1]) - above + 1
+				return {value, rank}
+			end
+		end
+		return {}
+	`)
+
+	leaguesKey := fmt.Sprintf("%s:leagues", leaderboardID)
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID, leaguesKey}, memberID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, 0, NewMemberNotFound(leaderboardID, memberID)
+		}
+		return nil, 0, fmt.Errorf("Retrieval of member league failed: %v", err)
+	}
+
+	values := result.([]interface{})
+	if len(values) == 0 {
+		return nil, 0, ErrMemberNotInLeague
+	}
+	league := &League{}
+	err = json.Unmarshal([]byte(values[0].(string)), league)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Invalid league: %v", err)
+	}
+	return league, int(values[1].(int64)), nil
+}
+
+// SurroundingTiers are the league a member is in and the leagues right above and below it, as returned by
+// GetSurroundingTiers. GapToAbove is how many points the member needs to reach the league above and
+// GapToBelow how many points it has to lose to fall into the league below.
+type SurroundingTiers struct {
+	Current    *League `json:"current"`
+	Above      *League `json:"above"`
+	Below      *League `json:"below"`
+	GapToAbove int64   `json:"gapToAbove"`
+	GapToBelow int64   `json:"gapToBelow"`
+}
+
+// GetSurroundingTiers returns the league the member with the given ID is in along with the leagues right
+// above and below it. Above is nil if the member is in the top league and Below is nil if it is in the
+// bottom one. Returns ErrMemberNotInLeague if the member score is not in any registered league.
+func (c *Client) GetSurroundingTiers(ctx context.Context, leaderboardID string, memberID string) (*SurroundingTiers, error) {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	scoreCmd := pipe.ZScore(leaderboardID, memberID)
+	leaguesCmd := pipe.HVals(fmt.Sprintf("%s:leagues", leaderboardID))
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of member leagues failed: %v", err)
+	}
+	if scoreCmd.Err() == redis.Nil {
+		return nil, NewMemberNotFound(leaderboardID, memberID)
+	}
+
+	leagues := make([]*League, len(leaguesCmd.Val()))
+	for i, value := range leaguesCmd.Val() {
+		leagues[i] = &League{}
+		err = json.Unmarshal([]byte(value), leagues[i])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid league: %v", err)
+		}
+	}
+	sort.Slice(leagues, func(i, j int) bool {
+		return leagues[i].MinScore < leagues[j].MinScore
+	})
+
+	score := int64(scoreCmd.Val())
+	for i, league := range leagues {
+		if score < league.MinScore || score > league.MaxScore {
+			continue
+		}
+		tiers := &SurroundingTiers{Current: league}
+		if i+1 < len(leagues) {
+			tiers.Above = leagues[i+1]
+			tiers.GapToAbove = tiers.Above.MinScore - score
+		}
+		if i > 0 {
+			tiers.Below = leagues[i-1]
+			tiers.GapToBelow = score - tiers.Below.MaxScore
+		}
+		return tiers, nil
+	}
+	return nil, ErrMemberNotInLeague
+}
+
+// GetMemberScoreTrend fits a line to the last points entries of the member score history by least
+// squares, returning its slope in score units per second and its R² goodness of fit. Fails unless at
+// least two entries recorded at different times exist.
+func (c *Client) GetMemberScoreTrend(ctx context.Context, leaderboardID string, memberID string,
+	points int) (float64, float64, error) {
+	if points < 2 {
+		return 0, 0, fmt.Errorf("At least two points are needed to compute a trend")
+	}
+
+	historyKey := fmt.Sprintf("%s:history:%s", leaderboardID, memberID)
+	values, err := c.redisWithTracing(ctx).LRange(historyKey, int64(-points), -1).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("Retrieval of score history failed: %v", err)
+	}
+	if len(values) < 2 {
+		return 0, 0, fmt.Errorf("Member %s has fewer than two score history entries", memberID)
+	}
+
+	n := float64(len(values))
+	xs := make([]float64, len(values))
+	ys := make([]float64, len(values))
+	var first int64
+	var sumX, sumY float64
+	for i, value := range values {
+		entry := struct {
+			Timestamp int64   `json:"timestamp"`
+			Score     float64 `json:"score"`
+		}{}
+		err = json.Unmarshal([]byte(value), &entry)
+		if err != nil {
+			return 0, 0, fmt.Errorf("Invalid score history entry: %v", err)
+		}
+		// timestamps are taken relative to the first entry to keep the sums small
+		if i == 0 {
+			first = entry.Timestamp
+		}
+		xs[i] = float64(entry.Timestamp - first)
+		ys[i] = entry.Score
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+
+	meanX, meanY := sumX/n, sumY/n
+	var sxx, sxy, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, 0, fmt.Errorf("Member %s score history entries were all recorded at the same time", memberID)
+	}
+
+	slope := sxy / sxx
+	if syy == 0 {
+		return slope, 1, nil
+	}
+	return slope, sxy * sxy / (sxx * syy), nil
+}
+
+// SwapResult holds the scores of both members of VerifiedSwapScores before and after the swap
+type SwapResult struct {
+	MemberA      string `json:"memberA"`
+	MemberB      string `json:"memberB"`
+	ScoreABefore int64  `json:"scoreABefore"`
+	ScoreBBefore int64  `json:"scoreBBefore"`
+	ScoreAAfter  int64  `json:"scoreAAfter"`
+	ScoreBAfter  int64  `json:"scoreBAfter"`
+}
+
+// SwapVerificationError indicates the scores read back after a swap are not the swapped scores
+type SwapVerificationError struct {
+	LeaderboardID string
+	Result        *SwapResult
+}
+
+func (e *SwapVerificationError) Error() string {
+	return fmt.Sprintf("Swap of members %s and %s in leaderboard %s could not be verified: expected %d and %d, got %d and %d.",
+		e.Result.MemberA, e.Result.MemberB, e.LeaderboardID, e.Result.ScoreBBefore, e.Result.ScoreABefore,
+		e.Result.ScoreAAfter, e.Result.ScoreBAfter)
+}
+
+// VerifiedSwapScores atomically exchanges the scores of two members and reads them back to make sure the
+// swap took place, failing with SwapVerificationError otherwise. Both members must be in the leaderboard.
+func (c *Client) VerifiedSwapScores(ctx context.Context, leaderboardID string, memberA, memberB string) (*SwapResult, error) {
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the first member
+		-- ARGV[2] is the second member
+
+		local score_a = redis.call("ZSCORE", KEYS[1], ARGV[1])
+		if not score_a then
+			return {ARGV[1]}
+		end
+		local score_b = redis.call("ZSCORE", KEYS[1], ARGV[2])
+		if not score_b then
+			return {ARGV[2]}
+		end
+
+		redis.call("ZADD", KEYS[1], score_b, ARGV[1], score_a, ARGV[2])
+		return {
+			score_a, score_b,
+			redis.call("ZSCORE", KEYS[1], ARGV[1]), redis.call("ZSCORE", KEYS[1], ARGV[2])
+		}
+	`)
+
+	c.InvalidateCache(leaderboardID)
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, memberA, memberB).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Swap of member scores failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	if len(res) == 1 {
+		return nil, NewMemberNotFound(leaderboardID, res[0].(string))
+	}
+	scores := make([]int64, len(res))
+	for i := range res {
+		score, err := strconv.ParseFloat(res[i].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+		scores[i] = int64(score)
+	}
+
+	swap := &SwapResult{
+		MemberA:      memberA,
+		MemberB:      memberB,
+		ScoreABefore: scores[0],
+		ScoreBBefore: scores[1],
+		ScoreAAfter:  scores[2],
+		ScoreBAfter:  scores[3],
+	}
+	if swap.ScoreAAfter != swap.ScoreBBefore || swap.ScoreBAfter != swap.ScoreABefore {
+		return swap, &SwapVerificationError{LeaderboardID: leaderboardID, Result: swap}
+	}
+	return swap, nil
+}
+
+// activityScore blends the score of a member with how recently it was updated. Members updated in the last
+// day count as one day old, and members that were never updated only get the score part.
+func activityScore(score int64, lastUpdated int64, now time.Time, recencyWeight float64) float64 {
+	activity := float64(score) * (1 - recencyWeight)
+	if lastUpdated == 0 {
+		return activity
+	}
+	ageInDays := now.Sub(time.Unix(lastUpdated, 0)).Hours() / 24
+	if ageInDays < 1 {
+		ageInDays = 1
+	}
+	return activity + recencyWeight/ageInDays
+}
+
+// GetActivityScore returns the activity score of a member, which is score * (1 - recencyWeight) +
+// (1 / ageInDays) * recencyWeight, where ageInDays is the time since the member was last updated.
+func (c *Client) GetActivityScore(ctx context.Context, leaderboardID string, memberID string,
+	recencyWeight float64) (float64, error) {
+	if recencyWeight < 0 || recencyWeight > 1 {
+		return 0, fmt.Errorf("Recency weight must be between 0 and 1")
+	}
+
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	scoreCmd := pipe.ZScore(leaderboardID, memberID)
+	lastUpdatedCmd := pipe.HGet(fmt.Sprintf("%s:lastupdated", leaderboardID), memberID)
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf("Retrieval of member activity failed: %v", err)
+	}
+	if scoreCmd.Err() == redis.Nil {
+		return 0, NewMemberNotFound(leaderboardID, memberID)
+	}
+
+	lastUpdated, _ := lastUpdatedCmd.Int64()
+	return activityScore(int64(scoreCmd.Val()), lastUpdated, time.Now(), recencyWeight), nil
+}
+
+// GetLeadersByActivityScore returns a page of members ranked by their activity score, as described in
+// GetActivityScore, from highest to lowest. Ties are ranked by public ID. The score of the returned
+// members is their leaderboard score, and their rank is the activity rank. Every member of the
+// leaderboard is read to rank them, so this is meant for small leaderboards.
+func (c *Client) GetLeadersByActivityScore(ctx context.Context, leaderboardID string, pageSize, page int,
+	recencyWeight float64) ([]*Member, error) {
+	if recencyWeight < 0 || recencyWeight > 1 {
+		return nil, fmt.Errorf("Recency weight must be between 0 and 1")
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	membersCmd := pipe.ZRangeWithScores(leaderboardID, 0, -1)
+	lastUpdatedCmd := pipe.HGetAll(fmt.Sprintf("%s:lastupdated", leaderboardID))
+	_, err := pipe.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of members activity failed: %v", err)
+	}
+
+	now := time.Now()
+	lastUpdated := lastUpdatedCmd.Val()
+	members := make([]*Member, len(membersCmd.Val()))
+	activities := make(map[string]float64, len(members))
+	for i, value := range membersCmd.Val() {
+		publicID := value.Member.(string)
+		updatedAt, _ := strconv.ParseInt(lastUpdated[publicID], 10, 64)
+		members[i] = &Member{PublicID: publicID, Score: int64(value.Score)}
+		activities[publicID] = activityScore(members[i].Score, updatedAt, now, recencyWeight)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		a, b := activities[members[i].PublicID], activities[members[j].PublicID]
+		if a != b {
+			return a > b
+		}
+		return members[i].PublicID < members[j].PublicID
+	})
+
+	start := (page - 1) * pageSize
+	if start >= len(members) {
+		return []*Member{}, nil
+	}
+	end := start + pageSize
+	if end > len(members) {
+		end = len(members)
+	}
+	for i := start; i < end; i++ {
+		members[i].Rank = i + 1
+	}
+	return members[start:end], nil
+}
+
+// GetMinScoreForTopN returns the score of the member ranked n-th, that is the minimum score needed to
+// be among the top n members. Returns 0 if the leaderboard has less than n members.
+func (c *Client) GetMinScoreForTopN(ctx context.Context, leaderboardID string, n int) (int64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("N must be a positive integer")
+	}
+	values, err := c.redisWithTracing(ctx).ZRevRangeWithScores(leaderboardID, int64(n-1), int64(n-1)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("Retrieval of minimum score for top %d failed: %v", n, err)
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+	return int64(values[0].Score), nil
+}
+
+// GetMaxScoreForBottomN returns the score of the member ranked n-th from the bottom, that is the maximum
+// score needed to be among the bottom n members. Returns 0 if the leaderboard has less than n members.
+func (c *Client) GetMaxScoreForBottomN(ctx context.Context, leaderboardID string, n int) (int64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("N must be a positive integer")
+	}
+	values, err := c.redisWithTracing(ctx).ZRangeWithScores(leaderboardID, int64(n-1), int64(n-1)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("Retrieval of maximum score for bottom %d failed: %v", n, err)
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+	return int64(values[0].Score), nil
+}
+
+// GetMembersWithScore returns up to limit members with exactly the given score, or all of them if limit is
+// zero or less. Tied members are ranked by public ID, starting right after the members with better scores
+// in the given order.
+func (c *Client) GetMembersWithScore(ctx context.Context, leaderboardID string, score int64, limit int64,
+	order string) ([]*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the score
+		-- ARGV[2] is the maximum number of members
+		-- ARGV[3] is the order
+
+		local ahead
+		if ARGV[3] == "desc" then
+			ahead = redis.call("ZCOUNT", KEYS[1], "(" .. ARGV[1], "+inf")
+		else
+			ahead = redis.call("ZCOUNT", KEYS[1], "-inf", "(" .. ARGV[1])
+		end
+		local members = redis.call("ZRANGEBYSCORE", KEYS[1], ARGV[1], ARGV[1], "LIMIT", 0, ARGV[2])
+		table.insert(members, 1, ahead)
+		return members
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, score, limit, order).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of members with score %d failed: %v", score, err)
+	}
+
+	res := result.([]interface{})
+	ahead := int(res[0].(int64))
+	members := make([]*Member, len(res)-1)
+	for i := range members {
+		members[i] = &Member{PublicID: res[i+1].(string), Score: score, Rank: ahead + i + 1}
+	}
+	return members, nil
+}
+
+// WeightedMetric is the weight of a member metric in RankByWeightedMetrics
+type WeightedMetric struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// RecordMemberMetric stores the value of a metric of the member with the given ID, such as its kills or
+// deaths, to be used by RankByWeightedMetrics
+func (c *Client) RecordMemberMetric(ctx context.Context, leaderboardID string, memberID string, metric string,
+	value int64) error {
+	metricsKey := fmt.Sprintf("%s:metrics:%s", leaderboardID, memberID)
+	err := c.redisWithTracing(ctx).HSet(metricsKey, metric, value).Err()
+	if err != nil {
+		return fmt.Errorf("Recording member metric failed: %v", err)
+	}
+	return nil
+}
+
+// RankByWeightedMetrics returns a page of the members of the leaderboard ranked by the weighted sum of their
+// metrics, from highest to lowest, with the weighted sum as their score. Metrics a member has no value for
+// count as zero. The weighted scores of all members are computed in a temporary sorted set by a Lua script,
+// so this is meant for small leaderboards.
+func (c *Client) RankByWeightedMetrics(ctx context.Context, leaderboardID string, weights []WeightedMetric,
+	pageSize, page int) ([]*Member, error) {
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("At least one metric weight must be provided.")
+	}
+	if page < 1 {
+		page = 1
+	}
+	encodedWeights, err := json.Marshal(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- KEYS[2] is the name of the temporary sorted set of weighted scores
+		-- ARGV[1] is the json encoded list of metric weights
+		-- ARGV[2] is the start offset of the page
+		-- ARGV[3] is the end offset of the page
+
+		local weights = cjson.decode(ARGV[1])
+		local names = {}
+		for i, weight in ipairs(weights) do
+			table.insert(names, weight["name"])
+		end
+
+		local members = redis.call("ZRANGE", KEYS[1], 0, -1)
+		for i, member in ipairs(members) do
+			local values = redis.call("HMGET", KEYS[1] .. ":metrics:" .. member, unpack(names))
+			local score = 0
+			for j, weight in ipairs(weights) do
+				score = score + (tonumber(values[j]) or 0) * weight["weight"]
+			end
+			redis.call("ZADD", KEYS[2], score, member)
+		end
+
+		local page = redis.call("ZREVRANGE", KEYS[2], ARGV[2], ARGV[3], "WITHSCORES")
+		redis.call("DEL", KEYS[2])
+		return page
+	`)
+
+	weightedKey := fmt.Sprintf("%s:weighted:%s", leaderboardID, uuid.NewV4().String())
+	startOffset := (page - 1) * pageSize
+	endOffset := startOffset + pageSize - 1
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID, weightedKey}, string(encodedWeights),
+		startOffset, endOffset).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Ranking by weighted metrics failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	members := make([]*Member, 0, len(res)/2)
+	for i := 0; i+1 < len(res); i += 2 {
+		score, _ := strconv.ParseFloat(res[i+1].(string), 64)
+		members = append(members, &Member{
+			PublicID: res[i].(string),
+			Score:    int64(score),
+			Rank:     startOffset + len(members) + 1,
+		})
+	}
+	return members, nil
+}
+
+// GetMemberQuartile returns the quartile of the ranking the member with the given ID is in, from 1 for the
+// best 25% of the members to 4 for the worst 25%
+func (c *Client) GetMemberQuartile(ctx context.Context, leaderboardID string, memberID string, order string) (int, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+
+	var operations = map[string]string{
+		"rank_desc": "ZREVRANK",
+		"rank_asc":  "ZRANK",
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is member's public ID
+
+		local rank = redis.call("` + operations["rank_"+order] + `", KEYS[1], ARGV[1])
+		if not rank then
+			return false
+		end
+		local total = redis.call("ZCARD", KEYS[1])
+		return math.min(math.floor(rank / total * 4) + 1, 4)
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, memberID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, NewMemberNotFound(leaderboardID, memberID)
+		}
+		return 0, fmt.Errorf("Retrieval of member quartile failed: %v", err)
+	}
+	return int(result.(int64)), nil
+}
+
+// reservedMetaPrefix is the prefix of metadata keys reserved for podium's own use
+const reservedMetaPrefix = "_internal_"
+
+func validateMetaKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("Metadata key must not be empty")
+	}
+	if strings.HasPrefix(key, reservedMetaPrefix) {
+		return fmt.Errorf("Metadata key %s must not start with %s", key, reservedMetaPrefix)
+	}
+	return nil
+}
+
+// SetLeaderboardMeta replaces the metadata of the leaderboard, such as its game mode or region, with the
+// given one. Keys starting with _internal_ are reserved.
+func (c *Client) SetLeaderboardMeta(ctx context.Context, leaderboardID string, meta map[string]string) error {
+	fields := make(map[string]interface{}, len(meta))
+	for key, value := range meta {
+		if err := validateMetaKey(key); err != nil {
+			return err
+		}
+		fields[key] = value
+	}
+
+	configKey := fmt.Sprintf("%s:config", leaderboardID)
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	pipe.Del(configKey)
+	if len(fields) > 0 {
+		pipe.HMSet(configKey, fields)
+	}
+	_, err := pipe.Exec()
+	if err != nil {
+		return fmt.Errorf("Setting leaderboard metadata failed: %v", err)
+	}
+	return nil
+}
+
+// SetLeaderboardMetaField sets a single field of the metadata of the leaderboard, keeping the other ones
+func (c *Client) SetLeaderboardMetaField(ctx context.Context, leaderboardID string, key, value string) error {
+	if err := validateMetaKey(key); err != nil {
+		return err
+	}
+	err := c.redisWithTracing(ctx).HSet(fmt.Sprintf("%s:config", leaderboardID), key, value).Err()
+	if err != nil {
+		return fmt.Errorf("Setting leaderboard metadata failed: %v", err)
+	}
+	return nil
+}
+
+// GetLeaderboardMeta returns the metadata of the leaderboard, which is empty if none was set
+func (c *Client) GetLeaderboardMeta(ctx context.Context, leaderboardID string) (map[string]string, error) {
+	meta, err := c.redisWithTracing(ctx).HGetAll(fmt.Sprintf("%s:config", leaderboardID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of leaderboard metadata failed: %v", err)
+	}
+	return meta, nil
+}
+
+// changeLogMaxEntries is roughly how many entries the change log of each leaderboard keeps, older entries are
+// dropped as new ones are appended. TrimChangeLog keeps fewer of them.
+const changeLogMaxEntries = 100000
+
+// ChangeLogEnabled returns whether score changes are appended to the leaderboard change log
+func (c *Client) ChangeLogEnabled() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.changeLog
+}
+
+// SetChangeLogEnabled sets whether score changes are appended to the leaderboard change log
+func (c *Client) SetChangeLogEnabled(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.changeLog = enabled
+}
+
+// ChangeLogEntry is a score change recorded in the change log of a leaderboard. OldScore is nil if the
+// member was not in the leaderboard before the change. Operation is either set or increment.
+type ChangeLogEntry struct {
+	ID        string `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	MemberID  string `json:"memberID"`
+	OldScore  *int64 `json:"oldScore"`
+	NewScore  int64  `json:"newScore"`
+	Operation string `json:"operation"`
+}
+
+// GetChangeLogs returns up to count entries of the leaderboard change log, oldest first, starting at the
+// entry with the given ID, included, or at the first entry if startID is empty. The change log is a redis
+// stream, so redis 5 or newer is required.
+func (c *Client) GetChangeLogs(ctx context.Context, leaderboardID string, startID string,
+	count int64) ([]ChangeLogEntry, error) {
+	if startID == "" {
+		startID = "-"
+	}
+	if count <= 0 {
+		return []ChangeLogEntry{}, nil
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the change log
+		-- ARGV[1] is the ID of the first entry
+		-- ARGV[2] is the maximum number of entries
+
+		return redis.call("XRANGE", KEYS[1], ARGV[1], "+", "COUNT", ARGV[2])
+	`)
+
+	changeLogKey := fmt.Sprintf("%s:changelog", leaderboardID)
+	result, err := script.Run(c.redisWithTracing(ctx), []string{changeLogKey}, startID, count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of change log failed: %v", err)
+	}
+
+	res := result.([]interface{})
+	entries := make([]ChangeLogEntry, len(res))
+	for i, value := range res {
+		values := value.([]interface{})
+		fields := values[1].([]interface{})
+		entry := ChangeLogEntry{ID: values[0].(string)}
+		for j := 0; j+1 < len(fields); j += 2 {
+			field := fields[j+1].(string)
+			switch fields[j].(string) {
+			case "timestamp":
+				entry.Timestamp, _ = strconv.ParseInt(field, 10, 64)
+			case "memberID":
+				entry.MemberID = field
+			case "oldScore":
+				if field != "" {
+					oldScore, _ := strconv.ParseInt(field, 10, 64)
+					entry.OldScore = &oldScore
+				}
+			case "newScore":
+				entry.NewScore, _ = strconv.ParseInt(field, 10, 64)
+			case "operation":
+				entry.Operation = field
+			}
+		}
+		entries[i] = entry
+	}
+	return entries, nil
+}
+
+// TrimChangeLog removes the oldest entries of the leaderboard change log, keeping at most maxLen of them
+func (c *Client) TrimChangeLog(ctx context.Context, leaderboardID string, maxLen int64) error {
+	if maxLen < 0 {
+		return fmt.Errorf("Max length must not be negative")
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the change log
+		-- ARGV[1] is the maximum number of entries kept
+
+		return redis.call("XTRIM", KEYS[1], "MAXLEN", ARGV[1])
+	`)
+
+	changeLogKey := fmt.Sprintf("%s:changelog", leaderboardID)
+	_, err := script.Run(c.redisWithTracing(ctx), []string{changeLogKey}, maxLen).Result()
+	if err != nil {
+		return fmt.Errorf("Trimming change log failed: %v", err)
+	}
+	return nil
+}
+
+// ComparisonMatrix compares the scores of every member of team A with every member of team B.
+// Differences[i][j] is the score of TeamA[i] minus the score of TeamB[j], and the summary counts how
+// many of those pairwise comparisons each team won and how many were draws.
+type ComparisonMatrix struct {
+	TeamA       []string  `json:"teamA"`
+	TeamB       []string  `json:"teamB"`
+	Differences [][]int64 `json:"differences"`
+	TeamAWins   int       `json:"teamAWins"`
+	TeamBWins   int       `json:"teamBWins"`
+	Draws       int       `json:"draws"`
+}
+
+// GetComparisonMatrix compares the score of each member of teamA with the score of each member of teamB.
+// All scores are fetched in a single pipeline, and every member must be in the leaderboard.
+func (c *Client) GetComparisonMatrix(ctx context.Context, leaderboardID string, teamA, teamB []string) (*ComparisonMatrix, error) {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	memberIDs := append(append([]string{}, teamA...), teamB...)
+	scoreCmds := make([]*redis.FloatCmd, len(memberIDs))
+	for i, memberID := range memberIDs {
+		scoreCmds[i] = pipe.ZScore(leaderboardID, memberID)
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of team scores failed: %v", err)
+	}
+
+	scores := make([]int64, len(memberIDs))
+	for i, memberID := range memberIDs {
+		if scoreCmds[i].Err() == redis.Nil {
+			return nil, NewMemberNotFound(leaderboardID, memberID)
+		}
+		scores[i] = int64(scoreCmds[i].Val())
+	}
+
+	matrix := &ComparisonMatrix{TeamA: teamA, TeamB: teamB, Differences: make([][]int64, len(teamA))}
+	for i := range teamA {
+		matrix.Differences[i] = make([]int64, len(teamB))
+		for j := range teamB {
+			difference := scores[i] - scores[len(teamA)+j]
+			matrix.Differences[i][j] = difference
+			switch {
+			case difference > 0:
+				matrix.TeamAWins++
+			case difference < 0:
+				matrix.TeamBWins++
+			default:
+				matrix.Draws++
+			}
+		}
+	}
+	return matrix, nil
+}
+
+//AliasNotFoundError indicates no member is registered under an alias in a leaderboard
+type AliasNotFoundError struct {
+	LeaderboardID string
+	AliasID       string
+}
+
+func (e *AliasNotFoundError) Error() string {
+	return fmt.Sprintf("Could not find a member for alias %s in leaderboard %s.", e.AliasID, e.LeaderboardID)
+}
+
+//NewAliasNotFound returns a new error for alias not found
+func NewAliasNotFound(leaderboardID, aliasID string) *AliasNotFoundError {
+	return &AliasNotFoundError{
+		LeaderboardID: leaderboardID,
+		AliasID:       aliasID,
+	}
+}
+
+// RegisterMemberAlias maps aliasID to the canonical member ID in the leaderboard, so that the member can
+// also be retrieved by the alias, see GetMemberByAlias. Registering an existing alias again replaces its mapping.
+func (c *Client) RegisterMemberAlias(ctx context.Context, leaderboardID, canonicalID, aliasID string) error {
+	err := c.redisWithTracing(ctx).HSet(fmt.Sprintf("%s:aliases", leaderboardID), aliasID, canonicalID).Err()
+	if err != nil {
+		return fmt.Errorf("Registration of member alias failed: %v", err)
+	}
+	return nil
+}
+
+// GetMemberByAlias resolves aliasID to its canonical member ID and returns that member from the leaderboard
+func (c *Client) GetMemberByAlias(ctx context.Context, leaderboardID, aliasID, order string, includeTTL bool) (*Member, error) {
+	canonicalID, err := c.redisWithTracing(ctx).HGet(fmt.Sprintf("%s:aliases", leaderboardID), aliasID).Result()
+	if err == redis.Nil {
+		return nil, NewAliasNotFound(leaderboardID, aliasID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of member alias failed: %v", err)
+	}
+	return c.GetMember(ctx, leaderboardID, canonicalID, order, includeTTL)
+}
+
+// changeLogScanBatchSize is the number of change log entries read at a time when scanning the change log
+const changeLogScanBatchSize = 1000
+
+// scanChangeLog walks the change log of the leaderboard from the entry with ID start, included, reading
+// changeLogScanBatchSize entries per round trip so redis is never blocked for long, and calls visit with the
+// millisecond timestamp each entry ID starts with
+func (c *Client) scanChangeLog(ctx context.Context, leaderboardID string, start string,
+	visit func(timestamp int64)) error {
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the change log
+		-- ARGV[1] is the ID of the first entry
+		-- ARGV[2] is the maximum number of entries
+
+		local ids = {}
+		for _, entry in ipairs(redis.call("XRANGE", KEYS[1], ARGV[1], "+", "COUNT", ARGV[2])) do
+			table.insert(ids, entry[1])
+		end
+		return ids
+	`)
+
+	cli := c.redisWithTracing(ctx)
+	changeLogKey := fmt.Sprintf("%s:changelog", leaderboardID)
+	for {
+		result, err := script.Run(cli, []string{changeLogKey}, start, changeLogScanBatchSize).Result()
+		if err != nil {
+			return err
+		}
+
+		ids := result.([]interface{})
+		var timestamp, sequence int64
+		for _, id := range ids {
+			parts := strings.SplitN(id.(string), "-", 2)
+			timestamp, _ = strconv.ParseInt(parts[0], 10, 64)
+			sequence, _ = strconv.ParseInt(parts[1], 10, 64)
+			visit(timestamp)
+		}
+		if len(ids) < changeLogScanBatchSize {
+			return nil
+		}
+		start = fmt.Sprintf("%d-%d", timestamp, sequence+1)
+	}
+}
+
+// GetWriteThroughput returns the average number of score writes per second in the leaderboard during the last
+// window, counted from the change log, so the change log must be enabled, see WithChangeLog.
+func (c *Client) GetWriteThroughput(ctx context.Context, leaderboardID string, window time.Duration) (float64, error) {
+	windowMs := int64(window / time.Millisecond)
+	if windowMs <= 0 {
+		return 0, fmt.Errorf("Window must be at least one millisecond")
+	}
+
+	var count int64
+	startID := fmt.Sprintf("%d", time.Now().UnixNano()/int64(time.Millisecond)-windowMs)
+	err := c.scanChangeLog(ctx, leaderboardID, startID, func(timestamp int64) {
+		count++
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Retrieval of write throughput failed: %v", err)
+	}
+	return float64(count) / window.Seconds(), nil
+}
+
+// GetPeakWriteThroughput returns the highest number of score writes the leaderboard received within a single
+// second, counted from the change log entry with the given ID, included, or from the first entry if sinceID
+// is empty. The change log must be enabled, see WithChangeLog.
+func (c *Client) GetPeakWriteThroughput(ctx context.Context, leaderboardID string, sinceID string) (float64, error) {
+	if sinceID == "" {
+		sinceID = "-"
+	}
+
+	var peak, current int64
+	second := int64(-1)
+	err := c.scanChangeLog(ctx, leaderboardID, sinceID, func(timestamp int64) {
+		if timestamp/1000 == second {
+			current++
+		} else {
+			second = timestamp / 1000
+			current = 1
+		}
+		if current > peak {
+			peak = current
+		}
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Retrieval of peak write throughput failed: %v", err)
+	}
+	return float64(peak), nil
+}
+
+// RewardTier is a prize tier given to the members ranked from MinRank to MaxRank, both included
+type RewardTier struct {
+	Name    string `json:"name"`
+	MinRank int    `json:"minRank"`
+	MaxRank int    `json:"maxRank"`
+}
+
+// validateRewardTiers checks that the tiers have unique names and rank ranges that neither overlap nor
+// leave gaps between them
+func validateRewardTiers(tiers []RewardTier) error {
+	sorted := make([]RewardTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinRank < sorted[j].MinRank
+	})
+
+	names := make(map[string]bool, len(sorted))
+	for i, tier := range sorted {
+		if names[tier.Name] {
+			return fmt.Errorf("Reward tier %s is duplicated", tier.Name)
+		}
+		names[tier.Name] = true
+		if tier.MinRank < 1 || tier.MaxRank < tier.MinRank {
+			return fmt.Errorf("Reward tier %s has invalid rank range %d-%d", tier.Name, tier.MinRank, tier.MaxRank)
+		}
+		if i > 0 && tier.MinRank <= sorted[i-1].MaxRank {
+			return fmt.Errorf("Reward tiers %s and %s overlap", sorted[i-1].Name, tier.Name)
+		}
+		if i > 0 && tier.MinRank > sorted[i-1].MaxRank+1 {
+			return fmt.Errorf("Reward tiers %s and %s are not contiguous", sorted[i-1].Name, tier.Name)
+		}
+	}
+	return nil
+}
+
+// GetMembersForReward returns the members ranked within each reward tier, keyed by tier name. The tiers must
+// have unique names and rank ranges that neither overlap nor leave gaps between them. Tiers past the end of
+// the leaderboard are returned with fewer members or empty.
+func (c *Client) GetMembersForReward(ctx context.Context, leaderboardID string, tiers []RewardTier, order string) (map[string][]*Member, error) {
+	if order != "desc" && order != "asc" {
+		order = "desc"
+	}
+	err := validateRewardTiers(tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	tierCmds := make([]*redis.ZSliceCmd, len(tiers))
+	for i, tier := range tiers {
+		if order == "desc" {
+			tierCmds[i] = pipe.ZRevRangeWithScores(leaderboardID, int64(tier.MinRank-1), int64(tier.MaxRank-1))
+		} else {
+			tierCmds[i] = pipe.ZRangeWithScores(leaderboardID, int64(tier.MinRank-1), int64(tier.MaxRank-1))
+		}
+	}
+	if len(tiers) > 0 {
+		_, err = pipe.Exec()
+		if err != nil {
+			return nil, fmt.Errorf("Retrieval of members for reward tiers failed: %v", err)
+		}
+	}
+
+	result := make(map[string][]*Member, len(tiers))
+	for i, tier := range tiers {
+		values := tierCmds[i].Val()
+		members := make([]*Member, len(values))
+		for j, value := range values {
+			members[j] = &Member{
+				PublicID: value.Member.(string),
+				Score:    int64(value.Score),
+				Rank:     tier.MinRank + j,
+			}
+		}
+		result[tier.Name] = members
+	}
+	return result, nil
+}
+
+// cardinalityBatchSize is how many members are read at a time when counting distinct scores
+const cardinalityBatchSize = 1000
+
+// ScoreCardinalityEnabled returns whether the scores written to each leaderboard are counted in a HyperLogLog
+func (c *Client) ScoreCardinalityEnabled() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.cardinality
+}
+
+// SetScoreCardinalityEnabled sets whether the scores written to each leaderboard are counted in a HyperLogLog
+func (c *Client) SetScoreCardinalityEnabled(enabled bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.cardinality = enabled
+}
+
+// GetScoreCardinality returns how many distinct score values the members of the leaderboard have. The exact
+// count walks the whole leaderboard. If approximate is true the count is instead read from the HyperLogLog
+// kept with WithScoreCardinality, in constant time and with a small standard error. The HyperLogLog can not
+// forget scores, so it counts every distinct score written since it was enabled, including scores no member
+// holds anymore.
+func (c *Client) GetScoreCardinality(ctx context.Context, leaderboardID string, approximate bool) (int64, error) {
+	if approximate {
+		pipe := c.redisWithTracing(ctx).TxPipeline()
+		countCmd := pipe.PFCount(fmt.Sprintf("%s:cardinality", leaderboardID))
+		_, err := pipe.Exec()
+		if err != nil {
+			return 0, fmt.Errorf("Retrieval of score cardinality failed: %v", err)
+		}
+		return countCmd.Val(), nil
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the leaderboard
+		-- ARGV[1] is the number of members read at a time
+
+		local batchSize = tonumber(ARGV[1])
+		local total = redis.call("ZCARD", KEYS[1])
+		local scores = {}
+		local count = 0
+		for start = 0, total - 1, batchSize do
+			local values = redis.call("ZRANGE", KEYS[1], start, start + batchSize - 1, "WITHSCORES")
+			for i = 2, #values, 2 do
+				if not scores[values[i]] then
+					scores[values[i]] = true
+					count = count + 1
+				end
+			end
+		end
+		return count
+	`)
+
+	result, err := script.Run(c.redisWithTracing(ctx), []string{leaderboardID}, cardinalityBatchSize).Result()
+	if err != nil {
+		return 0, fmt.Errorf("Retrieval of score cardinality failed: %v", err)
+	}
+	return result.(int64), nil
+}
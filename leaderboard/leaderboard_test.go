@@ -12,6 +12,7 @@ package leaderboard_test
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis"
@@ -587,7 +588,7 @@ var _ = Describe("Leaderboard Model", func() {
 				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), testLeaderboardID, "member_"+strconv.Itoa(i+1), int64(1234*i), false, "")
 				Expect(err).NotTo(HaveOccurred())
 			}
-			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, pageSize, 1, "desc")
+			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, pageSize, 1, "desc", false)
 			Expect(err).NotTo(HaveOccurred())
 
 			firstOnPage := members[0]
@@ -605,7 +606,7 @@ var _ = Describe("Leaderboard Model", func() {
 				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), testLeaderboardID, "member_"+strconv.Itoa(i+1), int64(1234*i), false, "")
 				Expect(err).NotTo(HaveOccurred())
 			}
-			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, pageSize, 1, "asc")
+			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, pageSize, 1, "asc", false)
 			Expect(err).NotTo(HaveOccurred())
 
 			firstOnPage := members[0]
@@ -623,7 +624,7 @@ var _ = Describe("Leaderboard Model", func() {
 				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), testLeaderboardID, "member_"+strconv.Itoa(i), 100, false, "")
 				Expect(err).NotTo(HaveOccurred())
 			}
-			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, pageSize, 1, "desc")
+			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, pageSize, 1, "desc", false)
 			Expect(err).NotTo(HaveOccurred())
 			firstAroundMe := members[0]
 			lastAroundMe := members[pageSize-1]
@@ -638,7 +639,7 @@ var _ = Describe("Leaderboard Model", func() {
 				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), testLeaderboardID, "member_"+strconv.Itoa(i), 100, false, "")
 				Expect(err).NotTo(HaveOccurred())
 			}
-			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, pageSize, -1, "desc")
+			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, pageSize, -1, "desc", false)
 			Expect(err).NotTo(HaveOccurred())
 			firstAroundMe := members[0]
 			lastAroundMe := members[pageSize-1]
@@ -652,14 +653,14 @@ var _ = Describe("Leaderboard Model", func() {
 				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), testLeaderboardID, "member_"+strconv.Itoa(i), 100, false, "")
 				Expect(err).NotTo(HaveOccurred())
 			}
-			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, 25, 99999, "desc")
+			members, err := leaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, 25, 99999, "desc", false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(members).To(HaveLen(0))
 		})
 
 		It("should fail if invalid connection to Redis", func() {
 			//testLeaderboard := NewClient(getFaultyRedis(), "test-leaderboard", 25)
-			_, err := faultyLeaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, 25, 1, "desc")
+			_, err := faultyLeaderboards.GetLeaders(NewEmptyCtx(), testLeaderboardID, 25, 1, "desc", false)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("connection refused"))
 		})
@@ -893,6 +894,74 @@ var _ = Describe("Leaderboard Model", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("connection refused"))
 		})
+
+		It("should remove a leaderboard and its auxiliary keys", func() {
+			leaderboardID := uuid.NewV4().String()
+			otherLeaderboardID := leaderboardID + "-other"
+
+			err := leaderboards.SetMembersScore(NewEmptyCtx(), leaderboardID, Members{
+				&Member{PublicID: "friend-1", Score: 10},
+				&Member{PublicID: "friend-2", Score: 20},
+			}, false, "100")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), otherLeaderboardID, "friend-1", 10, false, "100")
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, key := range []string{
+				"history:friend-1", "history:friend-2", "meta:season", "rankhistory:friend-1", "sessions:friend-1",
+				"archive:1-abcdef12", "archive:1-abcdef12:updates",
+			} {
+				err = redisClient.Client.Set(fmt.Sprintf("%s:%s", leaderboardID, key), "1", 0).Err()
+				Expect(err).NotTo(HaveOccurred())
+				err = redisClient.Client.Set(fmt.Sprintf("%s:%s", otherLeaderboardID, key), "1", 0).Err()
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			familyKeys := func(lbID string) []interface{} {
+				keys, err := redisClient.Client.Eval(`return redis.call("KEYS", ARGV[1])`, []string{}, lbID+":*").Result()
+				Expect(err).NotTo(HaveOccurred())
+				return append(keys.([]interface{}), lbID)
+			}
+			keysBefore := familyKeys(leaderboardID)
+			otherKeysBefore := familyKeys(otherLeaderboardID)
+
+			deleted, err := leaderboards.DeleteLeaderboardFamily(NewEmptyCtx(), leaderboardID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(int64(len(keysBefore))))
+			Expect(familyKeys(leaderboardID)).To(HaveLen(1))
+			exists, err := redisClient.Client.Exists(leaderboardID).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(Equal(int64(0)))
+			Expect(familyKeys(otherLeaderboardID)).To(HaveLen(len(otherKeysBefore)))
+		})
+
+		It("should unregister the member expirations of the leaderboard family", func() {
+			leaderboardID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardID, "member", 10, false, "100")
+			Expect(err).NotTo(HaveOccurred())
+			registered, err := redisClient.Client.SIsMember("expiration-sets", leaderboardID+":ttl").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(registered).To(BeTrue())
+
+			_, err = leaderboards.DeleteLeaderboardFamily(NewEmptyCtx(), leaderboardID)
+			Expect(err).NotTo(HaveOccurred())
+
+			registered, err = redisClient.Client.SIsMember("expiration-sets", leaderboardID+":ttl").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(registered).To(BeFalse())
+		})
+
+		It("should not fail when removing the family of a leaderboard that does not exist", func() {
+			deleted, err := leaderboards.DeleteLeaderboardFamily(NewEmptyCtx(), uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(int64(0)))
+		})
+
+		It("should fail to remove the leaderboard family if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.DeleteLeaderboardFamily(NewEmptyCtx(), uuid.NewV4().String())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
 	})
 
 	Describe("getting many members at once", func() {
@@ -902,7 +971,7 @@ var _ = Describe("Leaderboard Model", func() {
 				leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
 			}
 
-			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-10", "member-30", "member-20"}, "desc", false)
+			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-10", "member-30", "member-20"}, "desc", false, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(members).To(HaveLen(3))
 
@@ -925,7 +994,7 @@ var _ = Describe("Leaderboard Model", func() {
 				leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
 			}
 
-			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-10", "member-30", "member-20"}, "asc", false)
+			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-10", "member-30", "member-20"}, "asc", false, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(members).To(HaveLen(3))
 
@@ -952,7 +1021,7 @@ var _ = Describe("Leaderboard Model", func() {
 				leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, ttl)
 			}
 
-			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-10", "member-30", "member-20"}, "desc", true)
+			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-10", "member-30", "member-20"}, "desc", true, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(members).To(HaveLen(3))
 
@@ -974,7 +1043,7 @@ var _ = Describe("Leaderboard Model", func() {
 
 		It("should return empty list if invalid leaderboard id", func() {
 			lbID := uuid.NewV4().String()
-			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"test"}, "desc", false)
+			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"test"}, "desc", false, false)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(members).To(HaveLen(0))
@@ -987,7 +1056,7 @@ var _ = Describe("Leaderboard Model", func() {
 				leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
 			}
 
-			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-0", "invalid-member"}, "desc", false)
+			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-0", "invalid-member"}, "desc", false, false)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(members).To(HaveLen(1))
@@ -998,10 +1067,4487 @@ var _ = Describe("Leaderboard Model", func() {
 
 		It("should fail with faulty redis", func() {
 			lbID := uuid.NewV4().String()
-			_, err := faultyLeaderboards.GetMembers(NewEmptyCtx(), lbID, []string{}, "desc", false)
+			_, err := faultyLeaderboards.GetMembers(NewEmptyCtx(), lbID, []string{}, "desc", false, false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("checking members presence", func() {
+		It("should return which members are present in the leaderboard", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			presence, err := leaderboards.GetMembersBitset(NewEmptyCtx(), lbID, []string{"member-1", "member-9", "invalid-member"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(presence).To(HaveLen(3))
+			Expect(presence["member-1"]).To(BeTrue())
+			Expect(presence["member-9"]).To(BeTrue())
+			Expect(presence["invalid-member"]).To(BeFalse())
+		})
+
+		It("should build presence bitset with all members", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err := leaderboards.BuildPresenceBitset(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 10; i++ {
+				present, err := leaderboards.MemberInPresenceBitset(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(present).To(BeTrue())
+			}
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersBitset(NewEmptyCtx(), testLeaderboardID, []string{"member-1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaderboard summary", func() {
+		It("should return total, member, top members and neighbours", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 20; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			summary, err := leaderboards.GetLeaderboardSummary(NewEmptyCtx(), lbID, "member-10", 5, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary.TotalMembers).To(Equal(20))
+			Expect(summary.CurrentMember.Rank).To(Equal(11))
+			Expect(summary.CurrentMember.Score).To(Equal(int64(90)))
+			Expect(summary.TopMembers).To(HaveLen(3))
+			Expect(summary.TopMembers[0].PublicID).To(Equal("member-0"))
+			Expect(summary.TopMembers[2].Rank).To(Equal(3))
+			Expect(summary.Neighbours).To(HaveLen(5))
+			Expect(summary.Neighbours[0].PublicID).To(Equal("member-8"))
+			Expect(summary.Neighbours[0].Rank).To(Equal(9))
+			Expect(summary.Neighbours[2].PublicID).To(Equal("member-10"))
+		})
+
+		It("should return summary without member if member does not exist", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 2; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			summary, err := leaderboards.GetLeaderboardSummary(NewEmptyCtx(), lbID, "invalid-member", 5, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary.TotalMembers).To(Equal(2))
+			Expect(summary.CurrentMember).To(BeNil())
+			Expect(summary.TopMembers).To(HaveLen(2))
+			Expect(summary.Neighbours).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetLeaderboardSummary(NewEmptyCtx(), testLeaderboardID, "member-1", 5, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting rank band", func() {
+		It("should return rank range for a score delta", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i*10), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			minRank, maxRank, err := leaderboards.GetRankBand(NewEmptyCtx(), lbID, "member-5", 25, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(minRank).To(Equal(4))
+			Expect(maxRank).To(Equal(6))
+
+			minRank, maxRank, err = leaderboards.GetRankBand(NewEmptyCtx(), lbID, "member-5", -25, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(minRank).To(Equal(6))
+			Expect(maxRank).To(Equal(8))
+		})
+
+		It("should fail if member does not exist", func() {
+			lbID := uuid.NewV4().String()
+			_, _, err := leaderboards.GetRankBand(NewEmptyCtx(), lbID, "invalid-member", 10, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, _, err := faultyLeaderboards.GetRankBand(NewEmptyCtx(), testLeaderboardID, "member-1", 10, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaderboard gini coefficient", func() {
+		It("should return gini coefficient of the scores", func() {
+			lbID := uuid.NewV4().String()
+			for i, score := range []int64{0, 0, 0, 10} {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), score, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			gini, err := leaderboards.GetLeaderboardGini(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gini).To(BeNumerically("~", 0.75, 0.0001))
+		})
+
+		It("should return zero if all scores are equal", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), 10, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			gini, err := leaderboards.GetLeaderboardGini(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gini).To(Equal(0.0))
+		})
+
+		It("should return zero if single member", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-0", 10, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			gini, err := leaderboards.GetLeaderboardGini(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gini).To(Equal(0.0))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetLeaderboardGini(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("importing members score from CSV", func() {
+		It("should set scores of all members in batches", func() {
+			lbID := uuid.NewV4().String()
+			csvData := "member-1,100\nmember-2,200\nmember-3,300\n"
+
+			imported, err := leaderboards.BatchSetMembersScoreFromCSV(NewEmptyCtx(), lbID, strings.NewReader(csvData), "", 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imported).To(Equal(int64(3)))
+
+			member, err := leaderboards.GetMember(NewEmptyCtx(), lbID, "member-3", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Rank).To(Equal(1))
+			Expect(member.Score).To(Equal(int64(300)))
+		})
+
+		It("should import valid rows and report invalid ones", func() {
+			lbID := uuid.NewV4().String()
+			csvData := "member-1,100\nmember-2,invalid\nmember-3\nmember-4,400\n"
+
+			imported, err := leaderboards.BatchSetMembersScoreFromCSV(NewEmptyCtx(), lbID, strings.NewReader(csvData), "", 10)
+			Expect(imported).To(Equal(int64(2)))
+			Expect(err).To(HaveOccurred())
+			importErr, ok := err.(*ImportError)
+			Expect(ok).To(BeTrue())
+			Expect(importErr.Failures).To(HaveLen(2))
+			Expect(importErr.Failures[0].Row).To(Equal(2))
+			Expect(importErr.Failures[0].PublicID).To(Equal("member-2"))
+			Expect(importErr.Failures[1].Row).To(Equal(3))
+		})
+
+		It("should report rows of failed batches if invalid connection to Redis", func() {
+			imported, err := faultyLeaderboards.BatchSetMembersScoreFromCSV(NewEmptyCtx(), testLeaderboardID, strings.NewReader("member-1,100\n"), "", 10)
+			Expect(imported).To(Equal(int64(0)))
+			Expect(err).To(HaveOccurred())
+			importErr, ok := err.(*ImportError)
+			Expect(ok).To(BeTrue())
+			Expect(importErr.Failures[0].Err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("rotating leaderboard", func() {
+		It("should archive leaderboard and start a fresh one", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			archivedID, err := leaderboards.RotateLeaderboard(NewEmptyCtx(), lbID, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archivedID).To(HavePrefix(lbID + ":archive:"))
+
+			total, err := leaderboards.TotalMembers(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(0))
+
+			total, err = leaderboards.TotalMembers(NewEmptyCtx(), archivedID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(10))
+
+			ttl, err := redisClient.Client.TTL(archivedID).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically("~", time.Hour, time.Second))
+		})
+
+		It("should use a different archive for each rotation", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			firstID, err := leaderboards.RotateLeaderboard(NewEmptyCtx(), lbID, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 200, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			secondID, err := leaderboards.RotateLeaderboard(NewEmptyCtx(), lbID, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secondID).NotTo(Equal(firstID))
+
+			member, err := leaderboards.GetMember(NewEmptyCtx(), firstID, "member-1", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Score).To(Equal(int64(100)))
+		})
+
+		It("should move member data to the archive", func() {
+			lbID := uuid.NewV4().String()
+			peakLeaderboards := NewClientWithRedis(redisClient, WithPeakScore())
+			_, err := peakLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "100")
+			Expect(err).NotTo(HaveOccurred())
+
+			archivedID, err := peakLeaderboards.RotateLeaderboard(NewEmptyCtx(), lbID, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = peakLeaderboards.GetMemberLastUpdated(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+			_, err = peakLeaderboards.GetMemberLastUpdated(NewEmptyCtx(), archivedID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			peak, err := peakLeaderboards.GetMemberPeakScore(NewEmptyCtx(), archivedID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(peak).To(Equal(int64(100)))
+
+			for _, key := range []string{lbID + ":ttl", lbID + ":lastupdated", lbID + ":peak"} {
+				exists, err := redisClient.Client.Exists(key).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exists).To(Equal(int64(0)))
+			}
+			registered, err := redisClient.Client.SIsMember("expiration-sets", lbID+":ttl").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(registered).To(BeFalse())
+			ttl, err := redisClient.Client.TTL(archivedID + ":peak").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically("~", time.Hour, time.Second))
+		})
+
+		It("should fail if leaderboard does not exist", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.RotateLeaderboard(NewEmptyCtx(), lbID, time.Hour)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not exist"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.RotateLeaderboard(NewEmptyCtx(), testLeaderboardID, time.Hour)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("member rank history", func() {
+		It("should record and return rank snapshots", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err := leaderboards.RecordRankSnapshot(NewEmptyCtx(), lbID, "member-5", "desc")
+			Expect(err).NotTo(HaveOccurred())
+
+			history, err := leaderboards.GetRankHistory(NewEmptyCtx(), lbID, "member-5", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].Rank).To(Equal(6))
+			Expect(history[0].Timestamp).To(BeNumerically("~", time.Now().Unix(), 1))
+		})
+
+		It("should keep only the latest snapshots", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			historyKey := fmt.Sprintf("%s:rankhistory:member-1", lbID)
+			for i := 0; i < 40; i++ {
+				_, err = redisClient.Client.ZAdd(historyKey, redis.Z{Score: float64(i), Member: fmt.Sprintf("%d:%d", i, i+1)}).Result()
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err = leaderboards.RecordRankSnapshot(NewEmptyCtx(), lbID, "member-1", "desc")
+			Expect(err).NotTo(HaveOccurred())
+
+			count, err := redisClient.Client.ZCard(historyKey).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(30)))
+
+			history, err := leaderboards.GetRankHistory(NewEmptyCtx(), lbID, "member-1", 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(2))
+			Expect(history[0].Rank).To(Equal(40))
+			Expect(history[1].Rank).To(Equal(1))
+		})
+
+		It("should fail if the history limit is not positive", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = leaderboards.RecordRankSnapshot(NewEmptyCtx(), lbID, "member-1", "desc")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.GetRankHistory(NewEmptyCtx(), lbID, "member-1", 0)
+			Expect(err).To(HaveOccurred())
+			_, err = leaderboards.GetRankHistory(NewEmptyCtx(), lbID, "member-1", -1)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if member does not exist", func() {
+			lbID := uuid.NewV4().String()
+			err := leaderboards.RecordRankSnapshot(NewEmptyCtx(), lbID, "invalid-member", "desc")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.RecordRankSnapshot(NewEmptyCtx(), testLeaderboardID, "member-1", "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting member rank in union of leaderboards", func() {
+		It("should return rank of member as if leaderboards were one", func() {
+			lbA := uuid.NewV4().String()
+			lbB := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbA, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbA, "member-2", 150, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbB, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbB, "member-3", 180, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			rank, err := leaderboards.MultiLeaderboardUnionRank(NewEmptyCtx(), "member-1", []string{lbA, lbB}, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank).To(Equal(1))
+
+			rank, err = leaderboards.MultiLeaderboardUnionRank(NewEmptyCtx(), "member-2", []string{lbB, lbA}, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank).To(Equal(3))
+		})
+
+		It("should fail if member does not exist", func() {
+			_, err := leaderboards.MultiLeaderboardUnionRank(NewEmptyCtx(), "invalid-member", []string{uuid.NewV4().String()}, "desc")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.MultiLeaderboardUnionRank(NewEmptyCtx(), "member-1", []string{testLeaderboardID}, "desc")
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("connection refused"))
 		})
 	})
 
+	Describe("getting members with previous rank", func() {
+		It("should return rank of the previous read as previous rank", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			members, err := leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-5"}, "desc", false, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members[0].Rank).To(Equal(6))
+			Expect(members[0].PreviousRank).To(Equal(-1))
+
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-5", 1000, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err = leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-5"}, "desc", false, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members[0].Rank).To(Equal(1))
+			Expect(members[0].PreviousRank).To(Equal(6))
+		})
+
+		It("should return previous rank for leaders", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 5, 1, "desc", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-4", 1000, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 5, 1, "desc", true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members[0].PublicID).To(Equal("member-4"))
+			Expect(members[0].PreviousRank).To(Equal(5))
+			Expect(members[1].PublicID).To(Equal("member-0"))
+			Expect(members[1].PreviousRank).To(Equal(1))
+		})
+
+		It("should forget the previous rank of removed members", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-1"}, "desc", false, true)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-1"}, "asc", false, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = leaderboards.RemoveMember(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			exists, err := redisClient.Client.Exists(lbID + ":prevrank").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(Equal(int64(0)))
+		})
+
+		It("should expire previous ranks with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.GetLeaders(NewEmptyCtx(), lbID, 5, 1, "desc", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			ttl, err := redisClient.Client.TTL(lbID + ":prevrank").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+		})
+	})
+
+	Describe("compare and set member score", func() {
+		It("should set score if current score is the expected one", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 150, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			swapped, member, err := leaderboards.CompareAndSetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, 200, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(swapped).To(BeTrue())
+			Expect(member.Score).To(Equal(int64(200)))
+			Expect(member.Rank).To(Equal(1))
+		})
+
+		It("should not set score if current score is not the expected one", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			swapped, member, err := leaderboards.CompareAndSetMemberScore(NewEmptyCtx(), lbID, "member-1", 50, 200, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(swapped).To(BeFalse())
+			Expect(member.Score).To(Equal(int64(100)))
+
+			current, err := leaderboards.GetMember(NewEmptyCtx(), lbID, "member-1", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(current.Score).To(Equal(int64(100)))
+		})
+
+		It("should set score expiration if swapped", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			swapped, member, err := leaderboards.CompareAndSetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, 200, "100")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(swapped).To(BeTrue())
+			Expect(member.ExpireAt).To(BeNumerically("~", time.Now().Unix()+100, 1))
+		})
+
+		It("should update cached leaders and the change log if swapped", func() {
+			lbID := uuid.NewV4().String()
+			changeLogLeaderboards := NewClientWithRedis(redisClient, WithChangeLog())
+			_, err := changeLogLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = changeLogLeaderboards.WarmupLeaderboard(NewEmptyCtx(), lbID, 1, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			swapped, _, err := changeLogLeaderboards.CompareAndSetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, 200, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(swapped).To(BeTrue())
+
+			leaders, err := changeLogLeaderboards.GetLeaders(NewEmptyCtx(), lbID, 1, 1, "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(leaders[0].Score).To(Equal(int64(200)))
+
+			entries, err := changeLogLeaderboards.GetChangeLogs(NewEmptyCtx(), lbID, "", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(*entries[1].OldScore).To(Equal(int64(100)))
+			Expect(entries[1].NewScore).To(Equal(int64(200)))
+		})
+
+		It("should validate the new score", func() {
+			lbID := uuid.NewV4().String()
+			validatedLeaderboards := NewClientWithRedis(redisClient, WithScoreValidator(MaxIncrementValidator(50)))
+			_, err := validatedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 50, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = validatedLeaderboards.CompareAndSetMemberScore(NewEmptyCtx(), lbID, "member-1", 50, 200, "")
+			Expect(err).To(HaveOccurred())
+
+			current, err := leaderboards.GetMember(NewEmptyCtx(), lbID, "member-1", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(current.Score).To(Equal(int64(50)))
+		})
+
+		It("should fail if member does not exist", func() {
+			_, _, err := leaderboards.CompareAndSetMemberScore(NewEmptyCtx(), uuid.NewV4().String(), "invalid-member", 0, 10, "")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, _, err := faultyLeaderboards.CompareAndSetMemberScore(NewEmptyCtx(), testLeaderboardID, "member-1", 0, 10, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaderboard podium", func() {
+		It("should return gold, silver and bronze members", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			podium, err := leaderboards.GetLeaderboardPodium(NewEmptyCtx(), lbID, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podium.Gold.PublicID).To(Equal("member-0"))
+			Expect(podium.Silver.PublicID).To(Equal("member-1"))
+			Expect(podium.Bronze.PublicID).To(Equal("member-2"))
+			Expect(podium.Bronze.Rank).To(Equal(3))
+		})
+
+		It("should leave medals empty if leaderboard has fewer than 3 members", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-0", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			podium, err := leaderboards.GetLeaderboardPodium(NewEmptyCtx(), lbID, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podium.Gold.PublicID).To(Equal("member-0"))
+			Expect(podium.Silver).To(BeNil())
+			Expect(podium.Bronze).To(BeNil())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetLeaderboardPodium(NewEmptyCtx(), testLeaderboardID, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaders excluding members", func() {
+		It("should return page without excluded members keeping global ranks", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			members, err := leaderboards.GetLeadersExcluding(NewEmptyCtx(), lbID, 3, 1, "desc", []string{"member-0", "member-2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(3))
+			Expect(members[0].PublicID).To(Equal("member-1"))
+			Expect(members[0].Rank).To(Equal(2))
+			Expect(members[1].PublicID).To(Equal("member-3"))
+			Expect(members[2].PublicID).To(Equal("member-4"))
+			Expect(members[2].Rank).To(Equal(5))
+
+			members, err = leaderboards.GetLeadersExcluding(NewEmptyCtx(), lbID, 3, 3, "desc", []string{"member-0", "member-2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].PublicID).To(Equal("member-8"))
+			Expect(members[1].PublicID).To(Equal("member-9"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetLeadersExcluding(NewEmptyCtx(), testLeaderboardID, 3, 1, "desc", []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("setting members score conditionally", func() {
+		It("should skip members not in the leaderboard if they must exist", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			members := Members{
+				&Member{Score: 200, PublicID: "member-1"},
+				&Member{Score: 300, PublicID: "member-2"},
+			}
+			result, err := leaderboards.SetMembersScoreConditional(NewEmptyCtx(), lbID, members, false, "", true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Updated).To(HaveLen(1))
+			Expect(result.Updated[0].PublicID).To(Equal("member-1"))
+			Expect(result.Updated[0].Score).To(Equal(int64(200)))
+			Expect(result.Skipped).To(Equal([]string{"member-2"}))
+
+			total, err := leaderboards.TotalMembers(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(1))
+		})
+
+		It("should skip all members if none of them exist", func() {
+			lbID := uuid.NewV4().String()
+			members := Members{&Member{Score: 300, PublicID: "member-2"}}
+			result, err := leaderboards.SetMembersScoreConditional(NewEmptyCtx(), lbID, members, false, "", true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Updated).To(BeEmpty())
+			Expect(result.Skipped).To(Equal([]string{"member-2"}))
+		})
+
+		It("should create members if they do not need to exist", func() {
+			lbID := uuid.NewV4().String()
+			members := Members{&Member{Score: 300, PublicID: "member-2"}}
+			result, err := leaderboards.SetMembersScoreConditional(NewEmptyCtx(), lbID, members, false, "", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Updated).To(HaveLen(1))
+			Expect(result.Skipped).To(BeEmpty())
+			Expect(members[0].Rank).To(Equal(1))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			members := Members{&Member{Score: 300, PublicID: "member-2"}}
+			_, err := faultyLeaderboards.SetMembersScoreConditional(NewEmptyCtx(), testLeaderboardID, members, false, "", true)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+
+		It("should skip members whose score would not increase", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			members := Members{
+				&Member{Score: 200, PublicID: "member-1"},
+				&Member{Score: 100, PublicID: "member-2"},
+				&Member{Score: 50, PublicID: "member-3"},
+			}
+			result, err := leaderboards.SetMembersScoreIfHigher(NewEmptyCtx(), lbID, members, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Updated).To(HaveLen(2))
+			Expect(result.Updated[0]).To(Equal(&Member{PublicID: "member-1", Score: 200, Rank: 1}))
+			Expect(result.Updated[1]).To(Equal(&Member{PublicID: "member-3", Score: 50, Rank: 3}))
+			Expect(result.Skipped).To(Equal([]string{"member-2"}))
+		})
+
+		It("should fail to set higher scores if invalid connection to Redis", func() {
+			members := Members{&Member{Score: 300, PublicID: "member-2"}}
+			_, err := faultyLeaderboards.SetMembersScoreIfHigher(NewEmptyCtx(), testLeaderboardID, members, false, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting member at rank", func() {
+		It("should return the member at the given rank", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			member, err := leaderboards.GetMemberAtRank(NewEmptyCtx(), lbID, 4, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.PublicID).To(Equal("member-3"))
+			Expect(member.Score).To(Equal(int64(97)))
+			Expect(member.Rank).To(Equal(4))
+
+			member, err = leaderboards.GetMemberAtRank(NewEmptyCtx(), lbID, 1, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.PublicID).To(Equal("member-9"))
+		})
+
+		It("should fail if rank is out of bounds", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-0", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.GetMemberAtRank(NewEmptyCtx(), lbID, 0, "desc")
+			Expect(err).To(BeAssignableToTypeOf(&RankOutOfBoundsError{}))
+			_, err = leaderboards.GetMemberAtRank(NewEmptyCtx(), lbID, 2, "desc")
+			Expect(err).To(BeAssignableToTypeOf(&RankOutOfBoundsError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMemberAtRank(NewEmptyCtx(), testLeaderboardID, 1, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("backfilling members rank", func() {
+		It("should set rank of members without rank", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			members := Members{
+				&Member{PublicID: "member-3", Score: 1},
+				&Member{PublicID: "member-5", Rank: 42},
+				&Member{PublicID: "invalid-member"},
+			}
+			err := leaderboards.BackfillRanks(NewEmptyCtx(), lbID, members, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members[0].Rank).To(Equal(4))
+			Expect(members[0].Score).To(Equal(int64(1)))
+			Expect(members[1].Rank).To(Equal(42))
+			Expect(members[2].Rank).To(Equal(0))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.BackfillRanks(NewEmptyCtx(), testLeaderboardID, Members{&Member{PublicID: "member-1"}}, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaderboard quartiles", func() {
+		It("should return quartiles for even number of members", func() {
+			lbID := uuid.NewV4().String()
+			for i := 1; i <= 8; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			q1, q2, q3, err := leaderboards.GetLeaderboardQuartiles(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(q1).To(Equal(int64(2)))
+			Expect(q2).To(Equal(int64(4)))
+			Expect(q3).To(Equal(int64(6)))
+		})
+
+		It("should return quartiles for odd number of members", func() {
+			lbID := uuid.NewV4().String()
+			for i := 1; i <= 9; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			q1, q2, q3, err := leaderboards.GetLeaderboardQuartiles(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(q1).To(Equal(int64(3)))
+			Expect(q2).To(Equal(int64(5)))
+			Expect(q3).To(Equal(int64(7)))
+		})
+
+		It("should fail if leaderboard has fewer than 4 members", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, _, err = leaderboards.GetLeaderboardQuartiles(NewEmptyCtx(), lbID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fewer than 4 members"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, _, _, err := faultyLeaderboards.GetLeaderboardQuartiles(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting global rank among leaderboards", func() {
+		It("should return rank of member among all leaderboards members", func() {
+			lbA := uuid.NewV4().String()
+			lbB := uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbA, fmt.Sprintf("a-%d", i), int64(100-i*10), false, "")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbB, fmt.Sprintf("b-%d", i), int64(95-i*10), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			rank, total, err := leaderboards.GetGlobalRankAmongLeaderboards(NewEmptyCtx(), "b-1", []string{lbA, lbB}, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank).To(Equal(4))
+			Expect(total).To(Equal(10))
+
+			rank, _, err = leaderboards.GetGlobalRankAmongLeaderboards(NewEmptyCtx(), "b-1", []string{lbA, lbB}, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rank).To(Equal(7))
+		})
+
+		It("should fail if member is not in any leaderboard", func() {
+			_, _, err := leaderboards.GetGlobalRankAmongLeaderboards(NewEmptyCtx(), "invalid-member", []string{uuid.NewV4().String()}, "desc")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, _, err := faultyLeaderboards.GetGlobalRankAmongLeaderboards(NewEmptyCtx(), "member-1", []string{testLeaderboardID}, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("member average score", func() {
+		It("should set member score to the average of session scores", func() {
+			lbID := uuid.NewV4().String()
+			for _, score := range []int64{10, 20, 25} {
+				err := leaderboards.RecordSessionScore(NewEmptyCtx(), lbID, "member-1", score)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			average, err := leaderboards.GetMemberAverageScore(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(average).To(BeNumerically("~", 18.333, 0.001))
+
+			member, err := leaderboards.GetMember(NewEmptyCtx(), lbID, "member-1", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Score).To(Equal(int64(18)))
+		})
+
+		It("should set the average score like regular score updates", func() {
+			lbID := uuid.NewV4().String()
+			peakLeaderboards := NewClientWithRedis(redisClient, WithPeakScore())
+			err := peakLeaderboards.RecordSessionScore(NewEmptyCtx(), lbID, "member-1", 100)
+			Expect(err).NotTo(HaveOccurred())
+			err = peakLeaderboards.WarmupLeaderboard(NewEmptyCtx(), lbID, 1, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			err = peakLeaderboards.RecordSessionScore(NewEmptyCtx(), lbID, "member-1", 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			peak, err := peakLeaderboards.GetMemberPeakScore(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(peak).To(Equal(int64(100)))
+
+			leaders, err := peakLeaderboards.GetLeaders(NewEmptyCtx(), lbID, 1, 1, "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(leaders[0].Score).To(Equal(int64(50)))
+		})
+
+		It("should only keep the latest session scores", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 25; i++ {
+				score := int64(0)
+				if i >= 5 {
+					score = 100
+				}
+				err := leaderboards.RecordSessionScore(NewEmptyCtx(), lbID, "member-1", score)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			average, err := leaderboards.GetMemberAverageScore(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(average).To(Equal(100.0))
+		})
+
+		It("should fail if member has no session scores", func() {
+			_, err := leaderboards.GetMemberAverageScore(NewEmptyCtx(), uuid.NewV4().String(), "member-1")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.RecordSessionScore(NewEmptyCtx(), testLeaderboardID, "member-1", 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("checking member existence", func() {
+		It("should return whether member is in the leaderboard", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 0, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			exists, err := leaderboards.MemberExists(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+
+			exists, err = leaderboards.MemberExists(NewEmptyCtx(), lbID, "invalid-member")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.MemberExists(NewEmptyCtx(), testLeaderboardID, "member-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting top members above score", func() {
+		It("should return members with score above threshold", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i*10), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			members, err := leaderboards.GetTopMembersAboveScore(NewEmptyCtx(), lbID, 70, 10, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(4))
+			Expect(members[0].PublicID).To(Equal("member-0"))
+			Expect(members[3].PublicID).To(Equal("member-3"))
+			Expect(members[3].Rank).To(Equal(4))
+
+			members, err = leaderboards.GetTopMembersAboveScore(NewEmptyCtx(), lbID, 70, 2, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].PublicID).To(Equal("member-3"))
+			Expect(members[0].Rank).To(Equal(7))
+			Expect(members[1].Rank).To(Equal(8))
+		})
+
+		It("should return empty list if no member is above threshold", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 10, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := leaderboards.GetTopMembersAboveScore(NewEmptyCtx(), lbID, 70, 10, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetTopMembersAboveScore(NewEmptyCtx(), testLeaderboardID, 70, 10, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaderboard density", func() {
+		It("should return number of members in each score bucket", func() {
+			lbID := uuid.NewV4().String()
+			for i, score := range []int64{0, 1, 2, 3, 10, 19} {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), score, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			density, err := leaderboards.GetLeaderboardDensity(NewEmptyCtx(), lbID, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(density).To(HaveLen(2))
+			Expect(density[0]).To(Equal(DensityBucket{MinScore: 0, MaxScore: 9, Count: 4, Density: 0.4}))
+			Expect(density[1]).To(Equal(DensityBucket{MinScore: 10, MaxScore: 19, Count: 2, Density: 0.2}))
+		})
+
+		It("should return empty list for empty leaderboard", func() {
+			density, err := leaderboards.GetLeaderboardDensity(NewEmptyCtx(), uuid.NewV4().String(), 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(density).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetLeaderboardDensity(NewEmptyCtx(), testLeaderboardID, 2)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members with zero score", func() {
+		It("should return members with zero score and their count", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				score := int64(0)
+				if i < 4 {
+					score = int64(10 - i)
+				}
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), score, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			members, err := leaderboards.GetMembersWithZeroScore(NewEmptyCtx(), lbID, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(3))
+			Expect(members[0].Score).To(Equal(int64(0)))
+			Expect(members[0].Rank).To(Equal(5))
+			Expect(members[2].Rank).To(Equal(7))
+
+			count, err := leaderboards.CountMembersWithZeroScore(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(6)))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersWithZeroScore(NewEmptyCtx(), testLeaderboardID, 3)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+			_, err = faultyLeaderboards.CountMembersWithZeroScore(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("all-time top score", func() {
+		var topScoreLeaderboards *Client
+
+		BeforeEach(func() {
+			topScoreLeaderboards = NewClientWithRedis(redisClient, WithTopScoreEver())
+		})
+
+		It("should keep the highest score ever set", func() {
+			lbID := uuid.NewV4().String()
+			_, err := topScoreLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = topScoreLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 500, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = topScoreLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 10, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = topScoreLeaderboards.RemoveLeaderboard(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+
+			score, memberID, err := topScoreLeaderboards.GetTopScoreEver(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(500)))
+			Expect(memberID).To(Equal("member-2"))
+		})
+
+		It("should keep the highest score reached by increments", func() {
+			lbID := uuid.NewV4().String()
+			_, err := topScoreLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 100, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = topScoreLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 50, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			score, memberID, err := topScoreLeaderboards.GetTopScoreEver(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(150)))
+			Expect(memberID).To(Equal("member-1"))
+		})
+
+		It("should not keep the top score unless enabled", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			exists, err := redisClient.Client.Exists(fmt.Sprintf("%s:alltime", lbID)).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(Equal(int64(0)))
+		})
+
+		It("should expire the top score with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			_, err := topScoreLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			leaderboardTTL, err := redisClient.Client.TTL(lbID).Result()
+			Expect(err).NotTo(HaveOccurred())
+			ttl, err := redisClient.Client.TTL(fmt.Sprintf("%s:alltime", lbID)).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+			Expect(ttl).To(BeNumerically("~", leaderboardTTL, time.Second))
+		})
+
+		It("should record current top score", func() {
+			lbID := uuid.NewV4().String()
+			_, err := redisClient.Client.ZAdd(lbID, redis.Z{Score: 300, Member: "member-1"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = leaderboards.RecordTopScoreEver(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+
+			score, memberID, err := leaderboards.GetTopScoreEver(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(300)))
+			Expect(memberID).To(Equal("member-1"))
+		})
+
+		It("should return empty member if no score was recorded", func() {
+			_, memberID, err := leaderboards.GetTopScoreEver(NewEmptyCtx(), uuid.NewV4().String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(memberID).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, _, err := faultyLeaderboards.GetTopScoreEver(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members changed since a given time", func() {
+		var trackingLeaderboards *Client
+
+		BeforeEach(func() {
+			trackingLeaderboards = NewClientWithRedis(redisClient, WithUpdateTracking(time.Hour))
+		})
+
+		It("should return only members updated after the given time", func() {
+			lbID := uuid.NewV4().String()
+			_, err := trackingLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = trackingLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 200, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = redisClient.Client.ZAdd(lbID+":updates", redis.Z{Score: float64(time.Now().Add(-30 * time.Minute).Unix()), Member: "member-1"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := trackingLeaderboards.GetMembersChangedSince(NewEmptyCtx(), lbID, time.Now().Add(-time.Minute), "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(1))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+			Expect(members[0].Score).To(Equal(int64(200)))
+			Expect(members[0].Rank).To(Equal(1))
+		})
+
+		It("should return empty list if no member was updated", func() {
+			members, err := trackingLeaderboards.GetMembersChangedSince(NewEmptyCtx(), uuid.NewV4().String(), time.Now(), "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should drop updates older than the tracking window", func() {
+			lbID := uuid.NewV4().String()
+			_, err := redisClient.Client.ZAdd(lbID+":updates", redis.Z{Score: float64(time.Now().Add(-2 * time.Hour).Unix()), Member: "member-1"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+			_, err = trackingLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 200, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			values, err := redisClient.Client.ZRangeWithScores(lbID+":updates", 0, -1).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(HaveLen(1))
+			Expect(values[0].Member).To(Equal("member-2"))
+		})
+
+		It("should not track updates unless enabled", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			exists, err := redisClient.Client.Exists(lbID + ":updates").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(Equal(int64(0)))
+		})
+
+		It("should expire tracked updates with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			_, err := trackingLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			ttl, err := redisClient.Client.TTL(lbID + ":updates").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersChangedSince(NewEmptyCtx(), testLeaderboardID, time.Now(), "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaderboard page with cursor", func() {
+		It("should walk all members through cursors", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			page, err := leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, "", 2, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Members).To(HaveLen(2))
+			Expect(page.Members[0].PublicID).To(Equal("member-0"))
+			Expect(page.Members[1].Rank).To(Equal(2))
+			Expect(page.HasMore).To(BeTrue())
+
+			page, err = leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, page.NextCursor, 2, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Members).To(HaveLen(2))
+			Expect(page.Members[0].PublicID).To(Equal("member-2"))
+			Expect(page.Members[0].Rank).To(Equal(3))
+			Expect(page.HasMore).To(BeTrue())
+
+			page, err = leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, page.NextCursor, 2, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Members).To(HaveLen(1))
+			Expect(page.Members[0].PublicID).To(Equal("member-4"))
+			Expect(page.HasMore).To(BeFalse())
+		})
+
+		It("should not repeat members when a member is added before the cursor", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 4; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), 100, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			page, err := leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, "", 2, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Members[0].PublicID).To(Equal("member-0"))
+			Expect(page.Members[1].PublicID).To(Equal("member-1"))
+
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-new", 1, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			page, err = leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, page.NextCursor, 2, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Members).To(HaveLen(2))
+			Expect(page.Members[0].PublicID).To(Equal("member-2"))
+			Expect(page.Members[0].Rank).To(Equal(4))
+			Expect(page.Members[1].PublicID).To(Equal("member-3"))
+			Expect(page.HasMore).To(BeFalse())
+		})
+
+		It("should walk a large group of tied members", func() {
+			lbID := uuid.NewV4().String()
+			members := Members{}
+			for i := 0; i < 250; i++ {
+				members = append(members, &Member{PublicID: fmt.Sprintf("member-%03d", i), Score: 0})
+			}
+			err := leaderboards.SetMembersScore(NewEmptyCtx(), lbID, members, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			seen := map[string]bool{}
+			cursor := ""
+			for pages := 0; pages < 30; pages++ {
+				page, err := leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, cursor, 10, "desc")
+				Expect(err).NotTo(HaveOccurred())
+				for _, member := range page.Members {
+					Expect(seen[member.PublicID]).To(BeFalse())
+					seen[member.PublicID] = true
+				}
+				cursor = page.NextCursor
+				if !page.HasMore {
+					break
+				}
+			}
+			Expect(seen).To(HaveLen(250))
+		})
+
+		It("should continue after the tied members seen if the last member seen was removed", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 6; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), 100, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			page, err := leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, "", 2, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			page, err = leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, page.NextCursor, 2, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Members[1].PublicID).To(Equal("member-3"))
+
+			err = leaderboards.RemoveMember(NewEmptyCtx(), lbID, "member-3")
+			Expect(err).NotTo(HaveOccurred())
+
+			page, err = leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), lbID, page.NextCursor, 2, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Members).To(HaveLen(2))
+			Expect(page.Members[0].PublicID).To(Equal("member-4"))
+			Expect(page.Members[0].Rank).To(Equal(4))
+			Expect(page.Members[1].PublicID).To(Equal("member-5"))
+			Expect(page.HasMore).To(BeFalse())
+		})
+
+		It("should fail if cursor is invalid", func() {
+			_, err := leaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), testLeaderboardID, "invalid!", 2, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid cursor"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetLeaderboardPageWithCursor(NewEmptyCtx(), testLeaderboardID, "", 2, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting member relative rank", func() {
+		It("should return the fraction of members behind", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 4; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			relativeRank, err := leaderboards.GetMemberRelativeRank(NewEmptyCtx(), lbID, "member-0", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(relativeRank).To(Equal(1.0))
+
+			relativeRank, err = leaderboards.GetMemberRelativeRank(NewEmptyCtx(), lbID, "member-3", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(relativeRank).To(Equal(0.25))
+
+			relativeRank, err = leaderboards.GetMemberRelativeRank(NewEmptyCtx(), lbID, "member-3", "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(relativeRank).To(Equal(1.0))
+		})
+
+		It("should fail if member does not exist", func() {
+			_, err := leaderboards.GetMemberRelativeRank(NewEmptyCtx(), uuid.NewV4().String(), "invalid-member", "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should return relative ranks of many members", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 4; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			relativeRanks, err := leaderboards.GetMemberRelativeRankBatch(NewEmptyCtx(), lbID, []string{"member-1", "member-2", "invalid-member"}, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(relativeRanks).To(Equal(map[string]float64{"member-1": 0.75, "member-2": 0.5}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMemberRelativeRank(NewEmptyCtx(), testLeaderboardID, "member-1", "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetMemberRelativeRankBatch(NewEmptyCtx(), testLeaderboardID, []string{"member-1"}, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("sorting members by external score", func() {
+		It("should rerank members by the given scores", func() {
+			members := Members{
+				{PublicID: "member-1", Score: 300, Rank: 1},
+				{PublicID: "member-2", Score: 200, Rank: 2},
+				{PublicID: "member-3", Score: 100, Rank: 3},
+			}
+
+			sorted := SortMembersByExternalScore(members, map[string]int64{"member-1": 50, "member-3": 400}, "desc")
+			Expect(sorted).To(HaveLen(3))
+			Expect(sorted[0].PublicID).To(Equal("member-3"))
+			Expect(sorted[0].Rank).To(Equal(1))
+			Expect(sorted[1].PublicID).To(Equal("member-2"))
+			Expect(sorted[1].Rank).To(Equal(2))
+			Expect(sorted[2].PublicID).To(Equal("member-1"))
+			Expect(sorted[2].Rank).To(Equal(3))
+			Expect(sorted[2].Score).To(Equal(int64(300)))
+
+			sorted = SortMembersByExternalScore(members, map[string]int64{"member-1": 50, "member-3": 400}, "asc")
+			Expect(sorted[0].PublicID).To(Equal("member-1"))
+			Expect(sorted[2].PublicID).To(Equal("member-3"))
+		})
+
+		It("should not modify the given members", func() {
+			members := Members{
+				{PublicID: "member-1", Score: 300, Rank: 1},
+				{PublicID: "member-2", Score: 200, Rank: 2},
+			}
+
+			SortMembersByExternalScore(members, map[string]int64{"member-2": 400}, "desc")
+			Expect(members[0].PublicID).To(Equal("member-1"))
+			Expect(members[0].Rank).To(Equal(1))
+			Expect(members[1].Rank).To(Equal(2))
+		})
+	})
+
+	Describe("getting members by ID pattern", func() {
+		It("should return members whose IDs match the pattern", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 3; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("guild_a_%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("guild_b_%d", i), int64(200-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			members, err := leaderboards.GetMembersByIDPattern(NewEmptyCtx(), lbID, "guild_a_*", 10, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(3))
+			for _, member := range members {
+				Expect(member.PublicID).To(HavePrefix("guild_a_"))
+				if member.PublicID == "guild_a_0" {
+					Expect(member.Score).To(Equal(int64(100)))
+					Expect(member.Rank).To(Equal(4))
+				}
+			}
+		})
+
+		It("should return at most limit members", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("guild_a_%d", i), int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			members, err := leaderboards.GetMembersByIDPattern(NewEmptyCtx(), lbID, "guild_*", 2, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersByIDPattern(NewEmptyCtx(), testLeaderboardID, "*", 10, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting guild leaderboard", func() {
+		var lbID string
+		var guildMembership map[string]string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			scores := map[string]int64{"member-1": 100, "member-2": 50, "member-3": 120, "member-4": 10}
+			for memberID, score := range scores {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, score, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			guildMembership = map[string]string{
+				"member-1": "guild-a",
+				"member-2": "guild-a",
+				"member-3": "guild-b",
+				"member-4": "guild-c",
+				"member-5": "guild-c",
+			}
+		})
+
+		It("should rank guilds by total score", func() {
+			entries, err := leaderboards.GetGuildLeaderboard(NewEmptyCtx(), lbID, guildMembership, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]*GuildEntry{
+				{GuildID: "guild-a", TotalScore: 150, MemberCount: 2, Rank: 1},
+				{GuildID: "guild-b", TotalScore: 120, MemberCount: 1, Rank: 2},
+				{GuildID: "guild-c", TotalScore: 10, MemberCount: 1, Rank: 3},
+			}))
+		})
+
+		It("should rank guilds in ascending order", func() {
+			entries, err := leaderboards.GetGuildLeaderboard(NewEmptyCtx(), lbID, guildMembership, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(3))
+			Expect(entries[0].GuildID).To(Equal("guild-c"))
+			Expect(entries[0].Rank).To(Equal(1))
+		})
+
+		It("should return only top guilds", func() {
+			entries, err := leaderboards.GetGuildLeaderboardTopN(NewEmptyCtx(), lbID, guildMembership, 2, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[1].GuildID).To(Equal("guild-b"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetGuildLeaderboard(NewEmptyCtx(), testLeaderboardID, guildMembership, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("deleting member TTL", func() {
+		It("should remove member score expiration keeping its score and rank", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "100")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 200, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = leaderboards.DeleteMemberTTL(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			member, err := leaderboards.GetMember(NewEmptyCtx(), lbID, "member-1", "desc", true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Score).To(Equal(int64(100)))
+			Expect(member.Rank).To(Equal(2))
+			Expect(member.ExpireAt).To(Equal(0))
+		})
+
+		It("should fail if member score has no expiration", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = leaderboards.DeleteMemberTTL(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.DeleteMemberTTL(NewEmptyCtx(), testLeaderboardID, "member-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaders with decayed scores", func() {
+		It("should rank members by their decayed scores", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 1000, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 600, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			twoHalfLivesAgo := time.Now().Add(-2 * time.Hour).Unix()
+			_, err = redisClient.Client.HSet(lbID+":lastupdated", "member-1", twoHalfLivesAgo).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := leaderboards.GetLeadersWithDecay(NewEmptyCtx(), lbID, 10, 1, "desc", 3600)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+			Expect(members[0].Rank).To(Equal(1))
+			Expect(members[0].Score).To(BeNumerically("~", 600, 1))
+			Expect(members[1].PublicID).To(Equal("member-1"))
+			Expect(members[1].Rank).To(Equal(2))
+			Expect(members[1].Score).To(BeNumerically("~", 250, 1))
+
+			score, err := redisClient.Client.ZScore(lbID, "member-1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(float64(1000)))
+		})
+
+		It("should return the requested page", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			members, err := leaderboards.GetLeadersWithDecay(NewEmptyCtx(), lbID, 2, 2, "desc", 3600)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+			Expect(members[0].Rank).To(Equal(3))
+		})
+
+		It("should fail if half-life is not positive", func() {
+			_, err := leaderboards.GetLeadersWithDecay(NewEmptyCtx(), testLeaderboardID, 10, 1, "desc", 0)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetLeadersWithDecay(NewEmptyCtx(), testLeaderboardID, 10, 1, "desc", 3600)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting member last updated time", func() {
+		It("should return the time of the last score update", func() {
+			lbID := uuid.NewV4().String()
+			before := time.Now().Unix()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			lastUpdated, err := leaderboards.GetMemberLastUpdated(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastUpdated.Unix()).To(BeNumerically(">=", before))
+			Expect(lastUpdated.Unix()).To(BeNumerically("<=", time.Now().Unix()))
+		})
+
+		It("should forget the last update of removed members", func() {
+			lbID := uuid.NewV4().String()
+			for _, memberID := range []string{"member-1", "member-2", "member-3"} {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, 100, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err := leaderboards.RemoveMember(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			err = leaderboards.RemoveMembers(NewEmptyCtx(), lbID, []interface{}{"member-2"})
+			Expect(err).NotTo(HaveOccurred())
+
+			lastUpdatedTimes, err := leaderboards.BulkGetMembersLastUpdated(NewEmptyCtx(), lbID, []string{"member-1", "member-2", "member-3"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastUpdatedTimes).To(HaveLen(1))
+			Expect(lastUpdatedTimes).To(HaveKey("member-3"))
+		})
+
+		It("should expire last update times with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			ttl, err := redisClient.Client.TTL(lbID + ":lastupdated").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+		})
+
+		It("should fail if member score was never updated", func() {
+			lastUpdated, err := leaderboards.GetMemberLastUpdated(NewEmptyCtx(), uuid.NewV4().String(), "member-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+			Expect(lastUpdated.IsZero()).To(BeTrue())
+		})
+
+		It("should return the last updated time of many members", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = redisClient.Client.HSet(lbID+":lastupdated", "member-2", 1500000000).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			lastUpdatedTimes, err := leaderboards.BulkGetMembersLastUpdated(NewEmptyCtx(), lbID, []string{"member-1", "member-2", "member-3"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastUpdatedTimes).To(HaveLen(2))
+			Expect(lastUpdatedTimes["member-2"]).To(Equal(time.Unix(1500000000, 0)))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMemberLastUpdated(NewEmptyCtx(), testLeaderboardID, "member-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.BulkGetMembersLastUpdated(NewEmptyCtx(), testLeaderboardID, []string{"member-1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting leaderboard replication lag", func() {
+		It("should return the time the sentinel took to reach the replica", func() {
+			lag, err := GetLeaderboardLag(redisClient.Client, redisClient.Client, time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lag).To(BeNumerically("<", time.Second))
+
+			keys, err := redisClient.Client.Eval(`return redis.call("KEYS", ARGV[1])`, []string{}, "podium:lag-sentinel:*").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			faultyClient := redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+			_, err := GetLeaderboardLag(faultyClient, redisClient.Client, time.Second)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = GetLeaderboardLag(redisClient.Client, faultyClient, time.Second)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting nth member", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should return the leader when n is 1", func() {
+			member, err := leaderboards.GetNthMember(NewEmptyCtx(), lbID, 1, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.PublicID).To(Equal("member-0"))
+			Expect(member.Score).To(Equal(int64(100)))
+			Expect(member.Rank).To(Equal(1))
+		})
+
+		It("should return the last member when n is the number of members", func() {
+			member, err := leaderboards.GetNthMember(NewEmptyCtx(), lbID, 5, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.PublicID).To(Equal("member-4"))
+			Expect(member.Rank).To(Equal(5))
+
+			member, err = leaderboards.GetNthMember(NewEmptyCtx(), lbID, 5, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.PublicID).To(Equal("member-0"))
+		})
+
+		It("should fail if n is out of bounds", func() {
+			_, err := leaderboards.GetNthMember(NewEmptyCtx(), lbID, 0, "desc")
+			Expect(err).To(BeAssignableToTypeOf(&RankOutOfBoundsError{}))
+
+			_, err = leaderboards.GetNthMember(NewEmptyCtx(), lbID, 6, "desc")
+			Expect(err).To(BeAssignableToTypeOf(&RankOutOfBoundsError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetNthMember(NewEmptyCtx(), testLeaderboardID, 1, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting score gap", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			scores := map[string]int64{"member-1": 100, "member-2": 70, "member-3": 20}
+			for memberID, score := range scores {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, score, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should return the gap to the neighbours", func() {
+			gap, err := leaderboards.GetScoreGap(NewEmptyCtx(), lbID, "member-2", "up", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gap).To(Equal(int64(30)))
+
+			gap, err = leaderboards.GetScoreGap(NewEmptyCtx(), lbID, "member-2", "down", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gap).To(Equal(int64(50)))
+
+			gap, err = leaderboards.GetScoreGap(NewEmptyCtx(), lbID, "member-2", "up", "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gap).To(Equal(int64(50)))
+		})
+
+		It("should return 0 for the first and last members", func() {
+			gap, err := leaderboards.GetScoreGap(NewEmptyCtx(), lbID, "member-1", "up", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gap).To(Equal(int64(0)))
+
+			gap, err = leaderboards.GetScoreGap(NewEmptyCtx(), lbID, "member-3", "down", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gap).To(Equal(int64(0)))
+		})
+
+		It("should fail if member does not exist", func() {
+			_, err := leaderboards.GetScoreGap(NewEmptyCtx(), lbID, "invalid-member", "up", "desc")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if direction is invalid", func() {
+			_, err := leaderboards.GetScoreGap(NewEmptyCtx(), lbID, "member-1", "left", "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid direction"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetScoreGap(NewEmptyCtx(), testLeaderboardID, "member-1", "up", "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting multiple pages of members", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 7; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should return consecutive pages", func() {
+			pages, err := leaderboards.GetMembersMultiPage(NewEmptyCtx(), lbID, 2, 2, 3, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pages).To(HaveLen(2))
+			Expect(pages[0]).To(HaveLen(2))
+			Expect(pages[0][0].PublicID).To(Equal("member-2"))
+			Expect(pages[0][0].Rank).To(Equal(3))
+			Expect(pages[1][1].PublicID).To(Equal("member-5"))
+			Expect(pages[1][1].Rank).To(Equal(6))
+		})
+
+		It("should not return pages past the last one", func() {
+			pages, err := leaderboards.GetMembersMultiPage(NewEmptyCtx(), lbID, 3, 2, 10, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pages).To(HaveLen(2))
+			Expect(pages[0][0].PublicID).To(Equal("member-3"))
+			Expect(pages[1]).To(HaveLen(1))
+			Expect(pages[1][0].PublicID).To(Equal("member-0"))
+
+			pages, err = leaderboards.GetMembersMultiPage(NewEmptyCtx(), lbID, 3, 5, 10, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pages).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersMultiPage(NewEmptyCtx(), testLeaderboardID, 2, 1, 3, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members above and below a rank", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 6; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should return members right above the rank", func() {
+			members, err := leaderboards.GetMembersAboveRank(NewEmptyCtx(), lbID, 5, 2, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+			Expect(members[0].Rank).To(Equal(3))
+			Expect(members[1].PublicID).To(Equal("member-3"))
+			Expect(members[1].Rank).To(Equal(4))
+
+			members, err = leaderboards.GetMembersAboveRank(NewEmptyCtx(), lbID, 5, -1, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(4))
+			Expect(members[0].Rank).To(Equal(1))
+
+			members, err = leaderboards.GetMembersAboveRank(NewEmptyCtx(), lbID, 1, -1, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should return members right below the rank", func() {
+			members, err := leaderboards.GetMembersBelowRank(NewEmptyCtx(), lbID, 2, 2, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+			Expect(members[0].Rank).To(Equal(3))
+			Expect(members[1].Rank).To(Equal(4))
+
+			members, err = leaderboards.GetMembersBelowRank(NewEmptyCtx(), lbID, 2, -1, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(4))
+			Expect(members[0].PublicID).To(Equal("member-3"))
+			Expect(members[3].Rank).To(Equal(6))
+
+			members, err = leaderboards.GetMembersBelowRank(NewEmptyCtx(), lbID, 6, -1, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersAboveRank(NewEmptyCtx(), testLeaderboardID, 5, -1, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetMembersBelowRank(NewEmptyCtx(), testLeaderboardID, 5, -1, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("default score TTL", func() {
+		It("should use the default score TTL when none is given", func() {
+			lbID := uuid.NewV4().String()
+			ttlLeaderboards := NewClientWithRedis(redisClient, WithDefaultScoreTTL(100*time.Second))
+			Expect(ttlLeaderboards.GetDefaultScoreTTL()).To(Equal(100 * time.Second))
+
+			member, err := ttlLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.ExpireAt).To(BeNumerically("~", time.Now().Unix()+100, 1))
+
+			member, err = ttlLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 100, false, "inf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.ExpireAt).To(BeNumerically("~", time.Now().Unix()+100, 1))
+
+			member, err = ttlLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-3", 10, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.ExpireAt).To(BeNumerically("~", time.Now().Unix()+100, 1))
+		})
+
+		It("should prefer the given score TTL", func() {
+			lbID := uuid.NewV4().String()
+			ttlLeaderboards := NewClientWithRedis(redisClient, WithDefaultScoreTTL(100*time.Second))
+
+			member, err := ttlLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "10")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.ExpireAt).To(BeNumerically("~", time.Now().Unix()+10, 1))
+		})
+
+		It("should not expire scores after clearing the default score TTL", func() {
+			lbID := uuid.NewV4().String()
+			ttlLeaderboards := NewClientWithRedis(redisClient)
+			ttlLeaderboards.SetDefaultScoreTTL(100 * time.Second)
+			ttlLeaderboards.ClearDefaultScoreTTL()
+			Expect(ttlLeaderboards.GetDefaultScoreTTL()).To(BeZero())
+
+			member, err := ttlLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.ExpireAt).To(Equal(0))
+		})
+	})
+
+	Describe("getting absent members", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 3; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should return members that are not in the leaderboard", func() {
+			absent, err := leaderboards.GetMembersAbsent(NewEmptyCtx(), lbID, []string{"member-0", "member-5", "member-2", "member-6"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(absent).To(Equal([]string{"member-5", "member-6"}))
+		})
+
+		It("should return empty list if all members are present", func() {
+			absent, err := leaderboards.GetMembersAbsent(NewEmptyCtx(), lbID, []string{"member-0", "member-1", "member-2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(absent).To(BeEmpty())
+		})
+
+		It("should return all members if all are absent", func() {
+			absent, err := leaderboards.GetMembersAbsent(NewEmptyCtx(), lbID, []string{"member-5", "member-6"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(absent).To(Equal([]string{"member-5", "member-6"}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersAbsent(NewEmptyCtx(), testLeaderboardID, []string{"member-1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members around score range", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-10*i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should return members in the range and right outside it", func() {
+			members, err := leaderboards.GetAroundScoreRange(NewEmptyCtx(), lbID, 3, 50, 70, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(5))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+			Expect(members[0].Rank).To(Equal(3))
+			Expect(members[4].PublicID).To(Equal("member-6"))
+			Expect(members[4].Rank).To(Equal(7))
+		})
+
+		It("should expand the page evenly on both ends", func() {
+			members, err := leaderboards.GetAroundScoreRange(NewEmptyCtx(), lbID, 5, 60, 60, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(5))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+			Expect(members[4].PublicID).To(Equal("member-6"))
+		})
+
+		It("should shift the page at the ends of the leaderboard", func() {
+			members, err := leaderboards.GetAroundScoreRange(NewEmptyCtx(), lbID, 5, 100, 100, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(5))
+			Expect(members[0].PublicID).To(Equal("member-0"))
+			Expect(members[0].Rank).To(Equal(1))
+
+			members, err = leaderboards.GetAroundScoreRange(NewEmptyCtx(), lbID, 5, 100, 100, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(5))
+			Expect(members[4].PublicID).To(Equal("member-0"))
+			Expect(members[4].Rank).To(Equal(10))
+		})
+
+		It("should fail if min score is greater than max score", func() {
+			_, err := leaderboards.GetAroundScoreRange(NewEmptyCtx(), lbID, 5, 70, 50, "desc")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetAroundScoreRange(NewEmptyCtx(), testLeaderboardID, 5, 50, 70, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("leaderboard health check", func() {
+		It("should be healthy", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "100")
+			Expect(err).NotTo(HaveOccurred())
+
+			result := leaderboards.HealthCheck(NewEmptyCtx(), lbID)
+			Expect(result.Healthy).To(BeTrue())
+			Expect(result.Checks).To(HaveLen(3))
+			for _, check := range result.Checks {
+				Expect(check.OK).To(BeTrue())
+				Expect(check.Error).To(BeEmpty())
+			}
+		})
+
+		It("should be unhealthy if score TTL key has the wrong type", func() {
+			lbID := uuid.NewV4().String()
+			_, err := redisClient.Client.Set(lbID+":ttl", "invalid", 0).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			result := leaderboards.HealthCheck(NewEmptyCtx(), lbID)
+			Expect(result.Healthy).To(BeFalse())
+			Expect(result.Checks[2].Name).To(Equal("ttl"))
+			Expect(result.Checks[2].OK).To(BeFalse())
+			Expect(result.Checks[2].Error).To(ContainSubstring("string"))
+		})
+
+		It("should be unhealthy if invalid connection to Redis", func() {
+			result := faultyLeaderboards.HealthCheck(NewEmptyCtx(), testLeaderboardID)
+			Expect(result.Healthy).To(BeFalse())
+			Expect(result.Checks).To(HaveLen(1))
+			Expect(result.Checks[0].Name).To(Equal("ping"))
+			Expect(result.Checks[0].Error).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members into slice", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should write a page of members into the given slice", func() {
+			buffer := make(Members, 3)
+			n, err := leaderboards.GetMembersIntoSlice(NewEmptyCtx(), lbID, buffer, 3, 2, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(2))
+			Expect(buffer[0]).To(Equal(&Member{PublicID: "member-3", Score: 97, Rank: 4}))
+			Expect(buffer[1]).To(Equal(&Member{PublicID: "member-4", Score: 96, Rank: 5}))
+			Expect(buffer[2]).To(BeNil())
+		})
+
+		It("should reuse members already in the slice", func() {
+			buffer := Members{&Member{PublicID: "old", PreviousRank: 10}}
+			reused := buffer[0]
+			n, err := leaderboards.GetMembersIntoSlice(NewEmptyCtx(), lbID, buffer, 1, 1, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(1))
+			Expect(buffer[0]).To(BeIdenticalTo(reused))
+			Expect(buffer[0]).To(Equal(&Member{PublicID: "member-4", Score: 96, Rank: 1}))
+		})
+
+		It("should fail if slice is smaller than page size", func() {
+			_, err := leaderboards.GetMembersIntoSlice(NewEmptyCtx(), lbID, make(Members, 2), 3, 1, "desc")
+			Expect(err).To(Equal(ErrBufferTooSmall))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersIntoSlice(NewEmptyCtx(), testLeaderboardID, make(Members, 3), 3, 1, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("counting members by TTL bucket", func() {
+		It("should count member scores expiring in each bucket", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "60")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 100, false, "7200")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-3", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = redisClient.Client.ZAdd(lbID+":ttl", redis.Z{Score: float64(time.Now().Add(-time.Minute).Unix()), Member: "member-4"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			counts, err := leaderboards.GetMemberCountByTTLBucket(NewEmptyCtx(), lbID, []time.Duration{0, time.Hour, 24 * time.Hour})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal([]int64{1, 1, 2}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMemberCountByTTLBucket(NewEmptyCtx(), testLeaderboardID, []time.Duration{time.Hour})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("leaderboard heatmap", func() {
+		It("should count score updates by hour of the day", func() {
+			lbID := uuid.NewV4().String()
+			heatmapLeaderboards := NewClientWithRedis(redisClient, WithHeatmap())
+			Expect(heatmapLeaderboards.HeatmapEnabled()).To(BeTrue())
+
+			err := heatmapLeaderboards.SetMembersScore(NewEmptyCtx(), lbID, Members{
+				&Member{PublicID: "member-1", Score: 100},
+				&Member{PublicID: "member-2", Score: 200},
+			}, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = heatmapLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 10, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			heatmap, err := heatmapLeaderboards.GetLeaderboardHeatmap(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			var total int64
+			for _, count := range heatmap {
+				total += count
+			}
+			Expect(total).To(Equal(int64(3)))
+			Expect(heatmap[time.Now().UTC().Hour()]).To(BeNumerically(">", 0))
+		})
+
+		It("should expire the heatmap counters with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			heatmapLeaderboards := NewClientWithRedis(redisClient, WithHeatmap())
+			_, err := heatmapLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			keys, err := redisClient.Client.Eval(`return redis.call("KEYS", ARGV[1])`, []string{},
+				lbID+":heatmap:*").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(HaveLen(1))
+			ttl, err := redisClient.Client.TTL(keys.([]interface{})[0].(string)).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+		})
+
+		It("should not count score updates if heatmap is disabled", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			heatmap, err := leaderboards.GetLeaderboardHeatmap(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(heatmap).To(Equal([24]int64{}))
+		})
+
+		It("should reset the heatmap", func() {
+			lbID := uuid.NewV4().String()
+			heatmapLeaderboards := NewClientWithRedis(redisClient)
+			heatmapLeaderboards.SetHeatmapEnabled(true)
+			_, err := heatmapLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = heatmapLeaderboards.ResetHeatmap(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+
+			heatmap, err := heatmapLeaderboards.GetLeaderboardHeatmap(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(heatmap).To(Equal([24]int64{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetLeaderboardHeatmap(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			err = faultyLeaderboards.ResetHeatmap(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members updated in window", func() {
+		It("should return members updated within the window sorted by rank", func() {
+			lbID := uuid.NewV4().String()
+			trackingLeaderboards := NewClientWithRedis(redisClient, WithUpdateTracking(time.Hour))
+			for i := 0; i < 3; i++ {
+				_, err := trackingLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			_, err := redisClient.Client.ZAdd(lbID+":updates", redis.Z{Score: float64(time.Now().Add(-10 * time.Minute).Unix()), Member: "member-1"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := trackingLeaderboards.GetMembersUpdatedInWindow(NewEmptyCtx(), lbID, 5*time.Minute, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+			Expect(members[0].Rank).To(Equal(1))
+			Expect(members[1].PublicID).To(Equal("member-0"))
+			Expect(members[1].Rank).To(Equal(3))
+		})
+
+		It("should return empty list if no member was updated in the window", func() {
+			members, err := leaderboards.GetMembersUpdatedInWindow(NewEmptyCtx(), uuid.NewV4().String(), time.Minute, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersUpdatedInWindow(NewEmptyCtx(), testLeaderboardID, time.Minute, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("score validator", func() {
+		It("should reject score updates the validator rejects", func() {
+			lbID := uuid.NewV4().String()
+			validatedLeaderboards := NewClientWithRedis(redisClient, WithScoreValidator(MaxIncrementValidator(100)))
+
+			_, err := validatedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = validatedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 201, false, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exceeds the maximum of 100"))
+
+			_, err = validatedLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 101, "")
+			Expect(err).To(HaveOccurred())
+
+			member, err := validatedLeaderboards.GetMember(NewEmptyCtx(), lbID, "member-1", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Score).To(Equal(int64(100)))
+		})
+
+		It("should allow score updates the validator accepts", func() {
+			lbID := uuid.NewV4().String()
+			validatedLeaderboards := NewClientWithRedis(redisClient)
+			validatedLeaderboards.SetScoreValidator(MaxIncrementValidator(100))
+
+			_, err := validatedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			member, err := validatedLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 100, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Score).To(Equal(int64(200)))
+			_, err = validatedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 10, false, "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should call the validator with current and proposed scores", func() {
+			lbID := uuid.NewV4().String()
+			var calls [][]int64
+			validatedLeaderboards := NewClientWithRedis(redisClient, WithScoreValidator(func(memberID string, currentScore, proposedScore int64) error {
+				calls = append(calls, []int64{currentScore, proposedScore})
+				return nil
+			}))
+
+			_, err := validatedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = validatedLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 50, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([][]int64{{0, 100}, {100, 150}}))
+		})
+	})
+
+	Describe("getting rank distribution", func() {
+		It("should count members in each rank group", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 12; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			counts, err := leaderboards.GetRankDistribution(NewEmptyCtx(), lbID, []RankGroup{
+				{Start: 1, End: 1},
+				{Start: 2, End: 10},
+				{Start: 11, End: 100},
+				{Start: 50, End: 100},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal([]int64{1, 9, 2, 0}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetRankDistribution(NewEmptyCtx(), testLeaderboardID, []RankGroup{{Start: 1, End: 10}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("member peak score", func() {
+		It("should keep the highest score of each member", func() {
+			lbID := uuid.NewV4().String()
+			peakLeaderboards := NewClientWithRedis(redisClient, WithPeakScore())
+			Expect(peakLeaderboards.PeakScoreEnabled()).To(BeTrue())
+
+			_, err := peakLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = peakLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 50, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = peakLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 20, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			peak, err := peakLeaderboards.GetMemberPeakScore(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(peak).To(Equal(int64(100)))
+
+			_, err = peakLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 60, "")
+			Expect(err).NotTo(HaveOccurred())
+			peak, err = peakLeaderboards.GetMemberPeakScore(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(peak).To(Equal(int64(130)))
+		})
+
+		It("should return top members by peak score", func() {
+			lbID := uuid.NewV4().String()
+			peakLeaderboards := NewClientWithRedis(redisClient)
+			peakLeaderboards.SetPeakScoreEnabled(true)
+			scores := map[string][]int64{"member-1": {300, 10}, "member-2": {200}, "member-3": {100, 250}}
+			for memberID, memberScores := range scores {
+				for _, score := range memberScores {
+					_, err := peakLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, score, false, "")
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+
+			members, err := peakLeaderboards.GetTopNByPeakScore(NewEmptyCtx(), lbID, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{
+				{PublicID: "member-1", Score: 300, Rank: 1},
+				{PublicID: "member-3", Score: 250, Rank: 2},
+			}))
+		})
+
+		It("should expire peak scores with the leaderboard and drop removed members", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			peakLeaderboards := NewClientWithRedis(redisClient, WithPeakScore())
+			for _, memberID := range []string{"member-1", "member-2"} {
+				_, err := peakLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, 100, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			ttl, err := redisClient.Client.TTL(lbID + ":peak").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+
+			err = peakLeaderboards.RemoveMember(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			members, err := peakLeaderboards.GetTopNByPeakScore(NewEmptyCtx(), lbID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(1))
+			Expect(members[0].PublicID).To(Equal("member-2"))
+		})
+
+		It("should fail if member has no peak score", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.GetMemberPeakScore(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMemberPeakScore(NewEmptyCtx(), testLeaderboardID, "member-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetTopNByPeakScore(NewEmptyCtx(), testLeaderboardID, 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("compressing leaderboard", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for memberID, score := range map[string]float64{"member-a": 100, "member-b": 100.5, "member-c": 100.7, "member-d": 50} {
+				_, err := redisClient.Client.ZAdd(lbID, redis.Z{Score: score, Member: memberID}).Result()
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should keep the first tied member", func() {
+			removed, err := leaderboards.CompressLeaderboard(NewEmptyCtx(), lbID, "keep_first")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(removed).To(Equal(int64(2)))
+
+			members, err := redisClient.Client.ZRangeWithScores(lbID, 0, -1).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]redis.Z{{Score: 50, Member: "member-d"}, {Score: 100, Member: "member-a"}}))
+		})
+
+		It("should use the max score of tied members", func() {
+			removed, err := leaderboards.CompressLeaderboard(NewEmptyCtx(), lbID, "max")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(removed).To(Equal(int64(2)))
+
+			score, err := redisClient.Client.ZScore(lbID, "member-a").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(100.7))
+		})
+
+		It("should use the average score of tied members", func() {
+			_, err := leaderboards.CompressLeaderboard(NewEmptyCtx(), lbID, "average")
+			Expect(err).NotTo(HaveOccurred())
+
+			score, err := redisClient.Client.ZScore(lbID, "member-a").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(float64(100)))
+		})
+
+		It("should delete the member data of removed members and keep the all-time top scores", func() {
+			trackedLeaderboards := NewClientWithRedis(redisClient, WithPeakScore(), WithStreaks(), WithJoinTracking(),
+				WithTopScoreEver())
+			_, err := trackedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-b", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = trackedLeaderboards.GetMembers(NewEmptyCtx(), lbID, []string{"member-b"}, "desc", false, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = trackedLeaderboards.CompressLeaderboard(NewEmptyCtx(), lbID, "keep_first")
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, suffix := range []string{"lastupdated", "prevrank", "peak", "streak", "joined"} {
+				exists, err := redisClient.Client.Exists(lbID + ":" + suffix).Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exists).To(Equal(int64(0)), suffix)
+			}
+			score, memberID, err := trackedLeaderboards.GetTopScoreEver(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(100)))
+			Expect(memberID).To(Equal("member-b"))
+		})
+
+		It("should fail if strategy is invalid", func() {
+			_, err := leaderboards.CompressLeaderboard(NewEmptyCtx(), lbID, "min")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid compression strategy"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.CompressLeaderboard(NewEmptyCtx(), testLeaderboardID, "max")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting page containing member", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 7; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should return the page where the member is", func() {
+			result, err := leaderboards.GetPageContaining(NewEmptyCtx(), lbID, "member-3", 3, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Page).To(Equal(2))
+			Expect(result.Members).To(HaveLen(3))
+			Expect(result.Members[0].PublicID).To(Equal("member-3"))
+			Expect(result.Members[0].Rank).To(Equal(4))
+
+			result, err = leaderboards.GetPageContaining(NewEmptyCtx(), lbID, "member-0", 3, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Page).To(Equal(3))
+			Expect(result.Members).To(HaveLen(1))
+			Expect(result.Members[0].PublicID).To(Equal("member-0"))
+		})
+
+		It("should fail if member does not exist", func() {
+			_, err := leaderboards.GetPageContaining(NewEmptyCtx(), lbID, "invalid-member", 3, "desc")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetPageContaining(NewEmptyCtx(), testLeaderboardID, "member-1", 3, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting member score at a given time", func() {
+		It("should return the score the member had at the given time", func() {
+			lbID := uuid.NewV4().String()
+			historyKey := fmt.Sprintf("%s:history:member-1", lbID)
+			for _, entry := range []string{
+				`{"timestamp":1000,"score":10}`,
+				`{"timestamp":2000,"score":20}`,
+				`{"timestamp":3000,"score":30}`,
+			} {
+				_, err := redisClient.Client.RPush(historyKey, entry).Result()
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			score, err := leaderboards.GetMemberScoreAt(NewEmptyCtx(), lbID, "member-1", time.Unix(2500, 0))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(20)))
+
+			score, err = leaderboards.GetMemberScoreAt(NewEmptyCtx(), lbID, "member-1", time.Unix(3000, 0))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(30)))
+
+			_, err = leaderboards.GetMemberScoreAt(NewEmptyCtx(), lbID, "member-1", time.Unix(999, 0))
+			Expect(err).To(Equal(ErrNoHistoryBeforeTimestamp))
+		})
+
+		It("should keep score history when enabled", func() {
+			lbID := uuid.NewV4().String()
+			historyLeaderboards := NewClientWithRedis(redisClient, WithScoreHistory())
+			Expect(historyLeaderboards.ScoreHistoryEnabled()).To(BeTrue())
+
+			_, err := historyLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = historyLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 50, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, err := redisClient.Client.LRange(fmt.Sprintf("%s:history:member-1", lbID), 0, -1).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+
+			score, err := historyLeaderboards.GetMemberScoreAt(NewEmptyCtx(), lbID, "member-1", time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(150)))
+		})
+
+		It("should expire score history with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			historyLeaderboards := NewClientWithRedis(redisClient, WithScoreHistory())
+			_, err := historyLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			ttl, err := redisClient.Client.TTL(fmt.Sprintf("%s:history:member-1", lbID)).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+		})
+
+		It("should not keep score history when disabled", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.GetMemberScoreAt(NewEmptyCtx(), lbID, "member-1", time.Now())
+			Expect(err).To(Equal(ErrNoHistoryBeforeTimestamp))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMemberScoreAt(NewEmptyCtx(), testLeaderboardID, "member-1", time.Now())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting top tiers", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 20; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should split top members into tiers", func() {
+			tiers, err := leaderboards.GetTopTiers(NewEmptyCtx(), lbID, []float64{10, 20, 50}, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tiers).To(HaveLen(3))
+
+			Expect(tiers[0].TierIndex).To(Equal(0))
+			Expect(tiers[0].Members).To(HaveLen(2))
+			Expect(tiers[0].MaxScore).To(Equal(int64(100)))
+			Expect(tiers[0].MinScore).To(Equal(int64(99)))
+
+			Expect(tiers[1].Members).To(HaveLen(4))
+			Expect(tiers[1].Members[0].Rank).To(Equal(3))
+			Expect(tiers[1].MaxScore).To(Equal(int64(98)))
+			Expect(tiers[1].MinScore).To(Equal(int64(95)))
+
+			Expect(tiers[2].Members).To(HaveLen(10))
+			Expect(tiers[2].MinScore).To(Equal(int64(85)))
+		})
+
+		It("should split bottom members into tiers in ascending order", func() {
+			tiers, err := leaderboards.GetTopTiers(NewEmptyCtx(), lbID, []float64{1, 99}, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tiers[0].Members).To(HaveLen(1))
+			Expect(tiers[0].MinScore).To(Equal(int64(81)))
+			Expect(tiers[0].MaxScore).To(Equal(int64(81)))
+			Expect(tiers[1].Members).To(HaveLen(19))
+			Expect(tiers[1].MaxScore).To(Equal(int64(100)))
+		})
+
+		It("should fail if percentages add up to more than 100", func() {
+			_, err := leaderboards.GetTopTiers(NewEmptyCtx(), lbID, []float64{60, 50}, "desc")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetTopTiers(NewEmptyCtx(), testLeaderboardID, []float64{10}, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting progress to next rank", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			scores := map[string]int64{"member-1": 200, "member-2": 125, "member-3": 100}
+			for memberID, score := range scores {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, score, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should return progress between neighbour scores", func() {
+			progress, err := leaderboards.GetProgressToNextRank(NewEmptyCtx(), lbID, "member-2", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(progress).To(Equal(&RankProgress{
+				CurrentScore:      125,
+				NextRankScore:     200,
+				PreviousRankScore: 100,
+				Fraction:          0.25,
+			}))
+		})
+
+		It("should return full progress for the first member", func() {
+			progress, err := leaderboards.GetProgressToNextRank(NewEmptyCtx(), lbID, "member-1", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(progress.NextRankScore).To(Equal(int64(200)))
+			Expect(progress.PreviousRankScore).To(Equal(int64(125)))
+			Expect(progress.Fraction).To(Equal(1.0))
+		})
+
+		It("should return no progress for the last member", func() {
+			progress, err := leaderboards.GetProgressToNextRank(NewEmptyCtx(), lbID, "member-3", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(progress.NextRankScore).To(Equal(int64(125)))
+			Expect(progress.PreviousRankScore).To(Equal(int64(100)))
+			Expect(progress.Fraction).To(Equal(0.0))
+		})
+
+		It("should fail if member does not exist", func() {
+			_, err := leaderboards.GetProgressToNextRank(NewEmptyCtx(), lbID, "invalid-member", "desc")
+			Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetProgressToNextRank(NewEmptyCtx(), testLeaderboardID, "member-1", "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("leaderboard growth", func() {
+		var joinLeaderboards *Client
+
+		BeforeEach(func() {
+			joinLeaderboards = NewClientWithRedis(redisClient, WithJoinTracking())
+		})
+
+		It("should record when members join the leaderboard", func() {
+			lbID := uuid.NewV4().String()
+			_, err := joinLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = redisClient.Client.ZAdd(lbID+":joined", redis.Z{Score: 1000, Member: "member-1"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = joinLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 200, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			joinedAt, err := redisClient.Client.ZScore(lbID+":joined", "member-1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joinedAt).To(Equal(float64(1000)))
+		})
+
+		It("should count members that joined within the window", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 3; i++ {
+				_, err := joinLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			_, err := redisClient.Client.ZAdd(lbID+":joined", redis.Z{Score: float64(time.Now().Add(-2 * time.Hour).Unix()), Member: "member-0"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+
+			joined, err := joinLeaderboards.GetGrowthRate(NewEmptyCtx(), lbID, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(joined).To(Equal(int64(2)))
+		})
+
+		It("should not record join times unless enabled", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			exists, err := redisClient.Client.Exists(lbID + ":joined").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(Equal(int64(0)))
+		})
+
+		It("should expire join times with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			_, err := joinLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			ttl, err := redisClient.Client.TTL(lbID + ":joined").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+		})
+
+		It("should return cumulative growth", func() {
+			lbID := uuid.NewV4().String()
+			for memberID, joinedAt := range map[string]float64{"member-1": 1000, "member-2": 1000, "member-3": 2000} {
+				_, err := redisClient.Client.ZAdd(lbID+":joined", redis.Z{Score: joinedAt, Member: memberID}).Result()
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			points, err := leaderboards.GetCumulativeGrowth(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(points).To(Equal([]GrowthPoint{{Timestamp: 1000, Total: 2}, {Timestamp: 2000, Total: 3}}))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetGrowthRate(NewEmptyCtx(), testLeaderboardID, time.Hour)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetCumulativeGrowth(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members in all leaderboards", func() {
+		It("should return members present in every leaderboard", func() {
+			lbIDs := []string{uuid.NewV4().String(), uuid.NewV4().String(), uuid.NewV4().String()}
+			scores := []map[string]int64{
+				{"member-1": 100, "member-2": 200, "member-3": 300, "member-4": 400},
+				{"member-1": 1, "member-2": 2, "member-3": 3},
+				{"member-2": 1000, "member-3": 10, "member-4": 10},
+			}
+			for i, lbScores := range scores {
+				for memberID, score := range lbScores {
+					_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbIDs[i], memberID, score, false, "")
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+
+			members, err := leaderboards.GetMembersInAllLeaderboards(NewEmptyCtx(), lbIDs, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].PublicID).To(Equal("member-3"))
+			Expect(members[0].Score).To(Equal(int64(300)))
+			Expect(members[0].Rank).To(Equal(2))
+			Expect(members[1].PublicID).To(Equal("member-2"))
+			Expect(members[1].Rank).To(Equal(3))
+
+			keys, err := redisClient.Client.Eval(`return redis.call("KEYS", ARGV[1])`, []string{}, "inter:*").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(BeEmpty())
+		})
+
+		It("should return empty list if no member is in every leaderboard", func() {
+			members, err := leaderboards.GetMembersInAllLeaderboards(NewEmptyCtx(), []string{uuid.NewV4().String(), uuid.NewV4().String()}, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should fail if no leaderboard is given", func() {
+			_, err := leaderboards.GetMembersInAllLeaderboards(NewEmptyCtx(), []string{}, "desc")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersInAllLeaderboards(NewEmptyCtx(), []string{testLeaderboardID}, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("bulk incrementing members score", func() {
+		It("should increment the scores of all members", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := leaderboards.BulkIncrementMembersScore(NewEmptyCtx(), lbID, Members{
+				&Member{PublicID: "member-1", Score: 10},
+				&Member{PublicID: "member-2", Score: 200},
+			}, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].Score).To(Equal(int64(110)))
+			Expect(members[0].Rank).To(Equal(2))
+			Expect(members[1].Score).To(Equal(int64(200)))
+			Expect(members[1].Rank).To(Equal(1))
+		})
+	})
+
+	Describe("recording match results", func() {
+		It("should update winner and loser scores", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 120, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			outcome, err := leaderboards.RecordMatchResult(NewEmptyCtx(), lbID, MatchResult{
+				WinnerID:    "member-1",
+				LoserID:     "member-2",
+				WinnerDelta: 30,
+				LoserDelta:  -20,
+			}, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outcome.Winner.PublicID).To(Equal("member-1"))
+			Expect(outcome.Winner.Score).To(Equal(int64(130)))
+			Expect(outcome.Winner.Rank).To(Equal(1))
+			Expect(outcome.Loser.PublicID).To(Equal("member-2"))
+			Expect(outcome.Loser.Score).To(Equal(int64(100)))
+			Expect(outcome.Loser.Rank).To(Equal(2))
+		})
+
+		It("should fail if loser would gain score", func() {
+			_, err := leaderboards.RecordMatchResult(NewEmptyCtx(), testLeaderboardID, MatchResult{
+				WinnerID: "member-1", LoserID: "member-2", WinnerDelta: 10, LoserDelta: 5,
+			}, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Loser delta"))
+		})
+
+		It("should fail if winner would lose score", func() {
+			_, err := leaderboards.RecordMatchResult(NewEmptyCtx(), testLeaderboardID, MatchResult{
+				WinnerID: "member-1", LoserID: "member-2", WinnerDelta: -10, LoserDelta: -5,
+			}, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Winner delta"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.RecordMatchResult(NewEmptyCtx(), testLeaderboardID, MatchResult{
+				WinnerID: "member-1", LoserID: "member-2", WinnerDelta: 10, LoserDelta: -5,
+			}, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting top scorer by tag", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(leaderboards.TagMember(NewEmptyCtx(), lbID, "member-2", "red", "blue")).To(Succeed())
+			Expect(leaderboards.TagMember(NewEmptyCtx(), lbID, "member-3", "red")).To(Succeed())
+			Expect(leaderboards.TagMember(NewEmptyCtx(), lbID, "member-9", "blue")).To(Succeed())
+		})
+
+		It("should return the best ranked member with the tag", func() {
+			member, err := leaderboards.GetTopScorerByTag(NewEmptyCtx(), lbID, "red", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member).To(Equal(&Member{PublicID: "member-2", Score: 98, Rank: 3}))
+
+			member, err = leaderboards.GetTopScorerByTag(NewEmptyCtx(), lbID, "red", "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member).To(Equal(&Member{PublicID: "member-3", Score: 97, Rank: 2}))
+		})
+
+		It("should ignore untagged members", func() {
+			Expect(leaderboards.UntagMember(NewEmptyCtx(), lbID, "member-2", "red")).To(Succeed())
+
+			member, err := leaderboards.GetTopScorerByTag(NewEmptyCtx(), lbID, "red", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.PublicID).To(Equal("member-3"))
+		})
+
+		It("should return nil if no member with the tag is in the leaderboard", func() {
+			member, err := leaderboards.GetTopScorerByTag(NewEmptyCtx(), lbID, "green", "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member).To(BeNil())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetTopScorerByTag(NewEmptyCtx(), testLeaderboardID, "red", "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			err = faultyLeaderboards.TagMember(NewEmptyCtx(), testLeaderboardID, "member-1", "red")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("Get members with expired TTL", func() {
+		It("should return members whose score expired but were not removed", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "expired", 100, false, "100")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "alive", 200, false, "100")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "forever", 50, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			expiredAt := time.Now().Unix() - 10
+			err = redisClient.Client.ZAdd(fmt.Sprintf("%s:ttl", lbID), redis.Z{Score: float64(expiredAt), Member: "expired"}).Err()
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := leaderboards.GetMembersWithExpiredTTL(NewEmptyCtx(), lbID, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(1))
+			Expect(members[0].PublicID).To(Equal("expired"))
+			Expect(members[0].Score).To(Equal(int64(100)))
+			Expect(members[0].Rank).To(Equal(2))
+			Expect(members[0].ExpireAt).To(Equal(int(expiredAt)))
+		})
+
+		It("should return empty list if no member expired", func() {
+			members, err := leaderboards.GetMembersWithExpiredTTL(NewEmptyCtx(), uuid.NewV4().String(), "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersWithExpiredTTL(NewEmptyCtx(), testLeaderboardID, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("member streaks", func() {
+		It("should count consecutive score improvements of each member", func() {
+			lbID := uuid.NewV4().String()
+			streakLeaderboards := NewClientWithRedis(redisClient, WithStreaks())
+			Expect(streakLeaderboards.StreakEnabled()).To(BeTrue())
+
+			scores := map[string][]int64{
+				"member-1": {10, 20, 30, 40},
+				"member-2": {10, 20, 15, 16},
+				"member-3": {50, 40},
+			}
+			for memberID, memberScores := range scores {
+				for _, score := range memberScores {
+					_, err := streakLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, score, false, "")
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+			_, err := streakLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-2", 5, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, err := streakLeaderboards.GetMembersWithHighestStreak(NewEmptyCtx(), lbID, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]StreakEntry{
+				{PublicID: "member-1", Streak: 3},
+				{PublicID: "member-2", Streak: 2},
+			}))
+
+			entries, err = streakLeaderboards.GetMembersWithHighestStreak(NewEmptyCtx(), lbID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(3))
+			Expect(entries[2]).To(Equal(StreakEntry{PublicID: "member-3", Streak: 0}))
+		})
+
+		It("should expire streaks with the leaderboard and drop removed members", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			streakLeaderboards := NewClientWithRedis(redisClient, WithStreaks())
+			for _, memberID := range []string{"member-1", "member-2"} {
+				_, err := streakLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, 10, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			ttl, err := redisClient.Client.TTL(lbID + ":streak").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+
+			err = streakLeaderboards.RemoveMembers(NewEmptyCtx(), lbID, []interface{}{"member-1"})
+			Expect(err).NotTo(HaveOccurred())
+			entries, err := streakLeaderboards.GetMembersWithHighestStreak(NewEmptyCtx(), lbID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]StreakEntry{{PublicID: "member-2", Streak: 0}}))
+		})
+
+		It("should not count streaks if disabled", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 10, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 20, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, err := leaderboards.GetMembersWithHighestStreak(NewEmptyCtx(), lbID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersWithHighestStreak(NewEmptyCtx(), testLeaderboardID, 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("top movers", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			memberIDs := []string{}
+			for i := 1; i <= 5; i++ {
+				memberID := fmt.Sprintf("member-%d", i)
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, int64(i*10), false, "")
+				Expect(err).NotTo(HaveOccurred())
+				memberIDs = append(memberIDs, memberID)
+			}
+			err := leaderboards.SnapshotMemberRanks(NewEmptyCtx(), lbID, append(memberIDs, "invalid-member"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return members that climbed the most", func() {
+			movers, err := leaderboards.GetTopMovers(NewEmptyCtx(), lbID, 2, "up")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(movers).To(Equal([]*MoverEntry{
+				{Member: &Member{PublicID: "member-1", Score: 100, Rank: 1}, OldRank: 5, Delta: 4},
+			}))
+		})
+
+		It("should return members that dropped the most", func() {
+			movers, err := leaderboards.GetTopMovers(NewEmptyCtx(), lbID, 2, "down")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(movers).To(Equal([]*MoverEntry{
+				{Member: &Member{PublicID: "member-2", Score: 20, Rank: 5}, OldRank: 4, Delta: -1},
+				{Member: &Member{PublicID: "member-3", Score: 30, Rank: 4}, OldRank: 3, Delta: -1},
+			}))
+		})
+
+		It("should compare against the latest snapshot", func() {
+			err := leaderboards.SnapshotMemberRanks(NewEmptyCtx(), lbID, []string{"member-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			movers, err := leaderboards.GetTopMovers(NewEmptyCtx(), lbID, 2, "up")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(movers).To(BeEmpty())
+		})
+
+		It("should fail with invalid direction", func() {
+			_, err := leaderboards.GetTopMovers(NewEmptyCtx(), lbID, 2, "sideways")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return members whose rank changed by more than delta", func() {
+			changed, err := leaderboards.GetMembersWithRankChangeLargerThan(NewEmptyCtx(), lbID, 1, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(Equal([]*MoverEntry{
+				{Member: &Member{PublicID: "member-1", Score: 100, Rank: 1}, OldRank: 5, Delta: 4},
+			}))
+
+			changed, err = leaderboards.GetMembersWithRankChangeLargerThan(NewEmptyCtx(), lbID, 0, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(HaveLen(5))
+			Expect(changed[0].Member.PublicID).To(Equal("member-1"))
+			Expect(changed[4]).To(Equal(&MoverEntry{Member: &Member{PublicID: "member-2", Score: 20, Rank: 5}, OldRank: 4, Delta: -1}))
+
+			changed, err = leaderboards.GetMembersWithRankChangeLargerThan(NewEmptyCtx(), lbID, 4, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeEmpty())
+		})
+
+		It("should reject asc order for rank changes", func() {
+			_, err := leaderboards.GetMembersWithRankChangeLargerThan(NewEmptyCtx(), lbID, 1, "asc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid order asc"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.SnapshotMemberRanks(NewEmptyCtx(), testLeaderboardID, []string{"member-1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetTopMovers(NewEmptyCtx(), testLeaderboardID, 2, "up")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetMembersWithRankChangeLargerThan(NewEmptyCtx(), testLeaderboardID, 2, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("member count above percentile", func() {
+		It("should count members in the top percentile", func() {
+			lbID := uuid.NewV4().String()
+			for i := 1; i <= 20; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			count, err := leaderboards.GetMemberCountAbovePercentile(NewEmptyCtx(), lbID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(2)))
+
+			count, err = leaderboards.GetMemberCountAbovePercentile(NewEmptyCtx(), lbID, 12.5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(3)))
+
+			count, err = leaderboards.GetMemberCountAbovePercentile(NewEmptyCtx(), lbID, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(20)))
+		})
+
+		It("should count members tied with the threshold", func() {
+			lbID := uuid.NewV4().String()
+			for i := 1; i <= 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i/5), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			count, err := leaderboards.GetMemberCountAbovePercentile(NewEmptyCtx(), lbID, 20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(6)))
+		})
+
+		It("should return zero for empty leaderboards", func() {
+			count, err := leaderboards.GetMemberCountAbovePercentile(NewEmptyCtx(), uuid.NewV4().String(), 50)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+
+		It("should fail with invalid percentile", func() {
+			_, err := leaderboards.GetMemberCountAbovePercentile(NewEmptyCtx(), testLeaderboardID, 0)
+			Expect(err).To(HaveOccurred())
+
+			_, err = leaderboards.GetMemberCountAbovePercentile(NewEmptyCtx(), testLeaderboardID, 100.5)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMemberCountAbovePercentile(NewEmptyCtx(), testLeaderboardID, 50)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("members by score range", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 1; i <= 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i*10), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		for _, lookup := range []string{"script", "pipeline"} {
+			lookup := lookup
+			getMembers := func(lbID string, minScore, maxScore, limit, offset int64, order string) ([]*Member, error) {
+				if lookup == "script" {
+					return leaderboards.GetMembersByScoreRange(NewEmptyCtx(), lbID, minScore, maxScore, limit, offset, order)
+				}
+				return leaderboards.GetMembersByScorePipeline(NewEmptyCtx(), lbID, minScore, maxScore, limit, offset, order)
+			}
+
+			It(fmt.Sprintf("should return members in the score range using a %s", lookup), func() {
+				members, err := getMembers(lbID, 30, 60, 0, 0, "desc")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(members).To(Equal([]*Member{
+					{PublicID: "member-6", Score: 60, Rank: 5},
+					{PublicID: "member-5", Score: 50, Rank: 6},
+					{PublicID: "member-4", Score: 40, Rank: 7},
+					{PublicID: "member-3", Score: 30, Rank: 8},
+				}))
+			})
+
+			It(fmt.Sprintf("should page through the score range using a %s", lookup), func() {
+				members, err := getMembers(lbID, 30, 60, 2, 1, "asc")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(members).To(Equal([]*Member{
+					{PublicID: "member-4", Score: 40, Rank: 4},
+					{PublicID: "member-5", Score: 50, Rank: 5},
+				}))
+			})
+
+			It(fmt.Sprintf("should return empty list if no member is in the range using a %s", lookup), func() {
+				members, err := getMembers(lbID, 1000, 2000, 0, 0, "desc")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(members).To(BeEmpty())
+			})
+		}
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersByScoreRange(NewEmptyCtx(), testLeaderboardID, 0, 100, 0, 0, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetMembersByScorePipeline(NewEmptyCtx(), testLeaderboardID, 0, 100, 0, 0, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("member leagues", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			for i := 1; i <= 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i*100), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			err := leaderboards.RegisterLeagues(NewEmptyCtx(), lbID, []League{
+				{Name: "bronze", MinScore: 0, MaxScore: 399},
+				{Name: "silver", MinScore: 400, MaxScore: 799},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the league of the member and its rank within the league", func() {
+			league, rank, err := leaderboards.GetMemberLeague(NewEmptyCtx(), lbID, "member-6")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(league).To(Equal(&League{Name: "silver", MinScore: 400, MaxScore: 799}))
+			Expect(rank).To(Equal(2))
+
+			league, rank, err = leaderboards.GetMemberLeague(NewEmptyCtx(), lbID, "member-3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(league.Name).To(Equal("bronze"))
+			Expect(rank).To(Equal(1))
+		})
+
+		It("should fail if the member is not in any league", func() {
+			_, _, err := leaderboards.GetMemberLeague(NewEmptyCtx(), lbID, "member-9")
+			Expect(err).To(Equal(ErrMemberNotInLeague))
+		})
+
+		It("should replace previously registered leagues", func() {
+			err := leaderboards.RegisterLeagues(NewEmptyCtx(), lbID, []League{{Name: "gold", MinScore: 800, MaxScore: 1000}})
+			Expect(err).NotTo(HaveOccurred())
+
+			league, rank, err := leaderboards.GetMemberLeague(NewEmptyCtx(), lbID, "member-9")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(league.Name).To(Equal("gold"))
+			Expect(rank).To(Equal(2))
+
+			_, _, err = leaderboards.GetMemberLeague(NewEmptyCtx(), lbID, "member-6")
+			Expect(err).To(Equal(ErrMemberNotInLeague))
+		})
+
+		It("should rank members of leagues with scores of 15 digits or more", func() {
+			bigID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), bigID, "member-1", 123456789012345, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), bigID, "member-2", 123456789012343, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			err = leaderboards.RegisterLeagues(NewEmptyCtx(), bigID, []League{{Name: "legend", MinScore: 0, MaxScore: 123456789012345}})
+			Expect(err).NotTo(HaveOccurred())
+
+			league, rank, err := leaderboards.GetMemberLeague(NewEmptyCtx(), bigID, "member-2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(league.Name).To(Equal("legend"))
+			Expect(rank).To(Equal(2))
+		})
+
+		It("should fail if the member is not in the leaderboard", func() {
+			_, _, err := leaderboards.GetMemberLeague(NewEmptyCtx(), lbID, "invalid-member")
+			Expect(err).To(Equal(NewMemberNotFound(lbID, "invalid-member")))
+		})
+
+		It("should fail with invalid leagues", func() {
+			err := leaderboards.RegisterLeagues(NewEmptyCtx(), lbID, []League{{Name: "gold", MinScore: 10, MaxScore: 0}})
+			Expect(err).To(HaveOccurred())
+
+			err = leaderboards.RegisterLeagues(NewEmptyCtx(), lbID, []League{{Name: "gold"}, {Name: "gold"}})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with overlapping leagues", func() {
+			err := leaderboards.RegisterLeagues(NewEmptyCtx(), lbID, []League{
+				{Name: "gold", MinScore: 500, MaxScore: 1000},
+				{Name: "silver", MinScore: 0, MaxScore: 500},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("League gold overlaps league silver"))
+
+			err = leaderboards.RegisterLeagues(NewEmptyCtx(), lbID, []League{
+				{Name: "gold", MinScore: 501, MaxScore: 1000},
+				{Name: "silver", MinScore: 0, MaxScore: 500},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.RegisterLeagues(NewEmptyCtx(), testLeaderboardID, []League{{Name: "gold"}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, _, err = faultyLeaderboards.GetMemberLeague(NewEmptyCtx(), testLeaderboardID, "member-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetSurroundingTiers(NewEmptyCtx(), testLeaderboardID, "member-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+
+		It("should return the leagues around the member league with the gaps to them", func() {
+			err := leaderboards.RegisterLeagues(NewEmptyCtx(), lbID, []League{
+				{Name: "gold", MinScore: 800, MaxScore: 1000},
+				{Name: "bronze", MinScore: 0, MaxScore: 399},
+				{Name: "silver", MinScore: 400, MaxScore: 799},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			bronze := &League{Name: "bronze", MinScore: 0, MaxScore: 399}
+			silver := &League{Name: "silver", MinScore: 400, MaxScore: 799}
+			gold := &League{Name: "gold", MinScore: 800, MaxScore: 1000}
+
+			tiers, err := leaderboards.GetSurroundingTiers(NewEmptyCtx(), lbID, "member-6")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tiers).To(Equal(&SurroundingTiers{
+				Current:    silver,
+				Above:      gold,
+				Below:      bronze,
+				GapToAbove: 200,
+				GapToBelow: 201,
+			}))
+
+			tiers, err = leaderboards.GetSurroundingTiers(NewEmptyCtx(), lbID, "member-9")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tiers).To(Equal(&SurroundingTiers{Current: gold, Below: silver, GapToBelow: 101}))
+
+			tiers, err = leaderboards.GetSurroundingTiers(NewEmptyCtx(), lbID, "member-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tiers).To(Equal(&SurroundingTiers{Current: bronze, Above: silver, GapToAbove: 300}))
+		})
+
+		It("should fail to get surrounding leagues if the member is not in any league", func() {
+			_, err := leaderboards.GetSurroundingTiers(NewEmptyCtx(), lbID, "member-9")
+			Expect(err).To(Equal(ErrMemberNotInLeague))
+
+			_, err = leaderboards.GetSurroundingTiers(NewEmptyCtx(), lbID, "invalid-member")
+			Expect(err).To(Equal(NewMemberNotFound(lbID, "invalid-member")))
+		})
+	})
+
+	Describe("member score trend", func() {
+		var lbID string
+		var historyKey string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			historyKey = fmt.Sprintf("%s:history:member-1", lbID)
+		})
+
+		It("should return the slope and fit of the recent score history", func() {
+			for _, entry := range []string{
+				`{"timestamp":900,"score":5000}`,
+				`{"timestamp":1000,"score":100}`,
+				`{"timestamp":1010,"score":120}`,
+				`{"timestamp":1020,"score":140}`,
+			} {
+				Expect(redisClient.Client.RPush(historyKey, entry).Err()).NotTo(HaveOccurred())
+			}
+
+			slope, r2, err := leaderboards.GetMemberScoreTrend(NewEmptyCtx(), lbID, "member-1", 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slope).To(BeNumerically("~", 2, 1e-9))
+			Expect(r2).To(BeNumerically("~", 1, 1e-9))
+		})
+
+		It("should return a lower fit for noisy score history", func() {
+			for _, entry := range []string{
+				`{"timestamp":0,"score":0}`,
+				`{"timestamp":10,"score":30}`,
+				`{"timestamp":20,"score":10}`,
+				`{"timestamp":30,"score":40}`,
+			} {
+				Expect(redisClient.Client.RPush(historyKey, entry).Err()).NotTo(HaveOccurred())
+			}
+
+			slope, r2, err := leaderboards.GetMemberScoreTrend(NewEmptyCtx(), lbID, "member-1", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slope).To(BeNumerically("~", 1, 1e-9))
+			Expect(r2).To(BeNumerically("~", 0.5, 1e-9))
+		})
+
+		It("should use the history recorded by score updates", func() {
+			historyLeaderboards := NewClientWithRedis(redisClient, WithScoreHistory())
+			_, err := historyLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = historyLeaderboards.GetMemberScoreTrend(NewEmptyCtx(), lbID, "member-1", 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fewer than two"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, _, err := faultyLeaderboards.GetMemberScoreTrend(NewEmptyCtx(), testLeaderboardID, "member-1", 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("get members around someone in multiple leaderboards", func() {
+		It("should get the same members as GetAroundMe in each leaderboard", func() {
+			leaderboardIDs := []string{uuid.NewV4().String(), uuid.NewV4().String()}
+			for i := 0; i < 101; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardIDs[0], "member_"+strconv.Itoa(i), int64(1234*i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardIDs[1], "member_"+strconv.Itoa(i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			for _, order := range []string{"desc", "asc"} {
+				result, err := leaderboards.GetAroundMeMultiLeaderboard(NewEmptyCtx(), "member_20", leaderboardIDs, 25, order)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				for _, leaderboardID := range leaderboardIDs {
+					members, err := leaderboards.GetAroundMe(NewEmptyCtx(), leaderboardID, 25, "member_20", order, false)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result[leaderboardID]).To(Equal(members))
+				}
+			}
+		})
+
+		It("should not include leaderboards the member is not in", func() {
+			leaderboardIDs := []string{uuid.NewV4().String(), uuid.NewV4().String()}
+			for i := 0; i < 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardIDs[0], "member_"+strconv.Itoa(i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			result, err := leaderboards.GetAroundMeMultiLeaderboard(NewEmptyCtx(), "member_2", leaderboardIDs, 5, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+			Expect(result[leaderboardIDs[0]]).To(HaveLen(5))
+			Expect(result[leaderboardIDs[0]][0].PublicID).To(Equal("member_1"))
+			Expect(result[leaderboardIDs[0]][0].Rank).To(Equal(2))
+
+			result, err = leaderboards.GetAroundMeMultiLeaderboard(NewEmptyCtx(), "invalid-member", leaderboardIDs, 5, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("should fail if faulty redis client", func() {
+			_, err := faultyLeaderboards.GetAroundMeMultiLeaderboard(NewEmptyCtx(), "qwe", []string{testLeaderboardID}, 10, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting score at rank percentile", func() {
+		It("should return the score at the given percentile of the ranking", func() {
+			leaderboardID := uuid.NewV4().String()
+			for i := 1; i <= 200; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardID, "member_"+strconv.Itoa(i), int64(i*10), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			score, err := leaderboards.GetScoreAtRankPercentile(NewEmptyCtx(), leaderboardID, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(2000)))
+
+			score, err = leaderboards.GetScoreAtRankPercentile(NewEmptyCtx(), leaderboardID, 12.5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(1750)))
+
+			score, err = leaderboards.GetScoreAtRankPercentile(NewEmptyCtx(), leaderboardID, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(10)))
+
+			score, err = leaderboards.GetP50Score(NewEmptyCtx(), leaderboardID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(1000)))
+
+			score, err = leaderboards.GetP75Score(NewEmptyCtx(), leaderboardID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(500)))
+
+			score, err = leaderboards.GetP95Score(NewEmptyCtx(), leaderboardID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(100)))
+
+			score, err = leaderboards.GetP99Score(NewEmptyCtx(), leaderboardID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(20)))
+		})
+
+		It("should return ErrEmptyLeaderboard if the leaderboard has no members", func() {
+			_, err := leaderboards.GetP50Score(NewEmptyCtx(), uuid.NewV4().String())
+			Expect(err).To(Equal(ErrEmptyLeaderboard))
+		})
+
+		It("should fail if the percentile is out of range", func() {
+			_, err := leaderboards.GetScoreAtRankPercentile(NewEmptyCtx(), testLeaderboardID, 101)
+			Expect(err).To(HaveOccurred())
+			_, err = leaderboards.GetScoreAtRankPercentile(NewEmptyCtx(), testLeaderboardID, -1)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if faulty redis client", func() {
+			_, err := faultyLeaderboards.GetP95Score(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("verified swap of member scores", func() {
+		It("should exchange the scores of both members", func() {
+			leaderboardID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardID, "member-a", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardID, "member-b", 200, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := leaderboards.VerifiedSwapScores(NewEmptyCtx(), leaderboardID, "member-a", "member-b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(&SwapResult{
+				MemberA:      "member-a",
+				MemberB:      "member-b",
+				ScoreABefore: 100,
+				ScoreBBefore: 200,
+				ScoreAAfter:  200,
+				ScoreBAfter:  100,
+			}))
+
+			member, err := leaderboards.GetMember(NewEmptyCtx(), leaderboardID, "member-a", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Score).To(Equal(int64(200)))
+			Expect(member.Rank).To(Equal(1))
+		})
+
+		It("should fail without changing scores if one of the members is missing", func() {
+			leaderboardID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardID, "member-a", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.VerifiedSwapScores(NewEmptyCtx(), leaderboardID, "member-a", "member-b")
+			Expect(err).To(Equal(NewMemberNotFound(leaderboardID, "member-b")))
+
+			_, err = leaderboards.VerifiedSwapScores(NewEmptyCtx(), leaderboardID, "member-b", "member-a")
+			Expect(err).To(Equal(NewMemberNotFound(leaderboardID, "member-b")))
+
+			member, err := leaderboards.GetMember(NewEmptyCtx(), leaderboardID, "member-a", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Score).To(Equal(int64(100)))
+			_, err = leaderboards.GetMember(NewEmptyCtx(), leaderboardID, "member-b", "desc", false)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if faulty redis client", func() {
+			_, err := faultyLeaderboards.VerifiedSwapScores(NewEmptyCtx(), testLeaderboardID, "member-a", "member-b")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting activity scores", func() {
+		var leaderboardID string
+
+		BeforeEach(func() {
+			leaderboardID = uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardID, "member-a", 10, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardID, "member-b", 11, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			tenDaysAgo := time.Now().Add(-10 * 24 * time.Hour).Unix()
+			err = redisClient.Client.HSet(leaderboardID+":lastupdated", "member-b", tenDaysAgo).Err()
+			Expect(err).NotTo(HaveOccurred())
+			err = redisClient.Client.ZAdd(leaderboardID, redis.Z{Score: 10, Member: "member-c"}).Err()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should blend the member score with its recency", func() {
+			activity, err := leaderboards.GetActivityScore(NewEmptyCtx(), leaderboardID, "member-a", 0.5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(activity).To(BeNumerically("~", 5.5, 0.001))
+
+			activity, err = leaderboards.GetActivityScore(NewEmptyCtx(), leaderboardID, "member-b", 0.5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(activity).To(BeNumerically("~", 5.55, 0.001))
+
+			activity, err = leaderboards.GetActivityScore(NewEmptyCtx(), leaderboardID, "member-c", 0.5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(activity).To(BeNumerically("~", 5, 0.001))
+
+			activity, err = leaderboards.GetActivityScore(NewEmptyCtx(), leaderboardID, "member-b", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(activity).To(BeNumerically("~", 11, 0.001))
+		})
+
+		It("should rank members by activity score", func() {
+			members, err := leaderboards.GetLeadersByActivityScore(NewEmptyCtx(), leaderboardID, 2, 1, 0.5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{
+				&Member{PublicID: "member-b", Score: 11, Rank: 1},
+				&Member{PublicID: "member-a", Score: 10, Rank: 2},
+			}))
+
+			members, err = leaderboards.GetLeadersByActivityScore(NewEmptyCtx(), leaderboardID, 2, 1, 0.9)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{
+				&Member{PublicID: "member-a", Score: 10, Rank: 1},
+				&Member{PublicID: "member-b", Score: 11, Rank: 2},
+			}))
+
+			members, err = leaderboards.GetLeadersByActivityScore(NewEmptyCtx(), leaderboardID, 2, 2, 0.9)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{&Member{PublicID: "member-c", Score: 10, Rank: 3}}))
+
+			members, err = leaderboards.GetLeadersByActivityScore(NewEmptyCtx(), leaderboardID, 2, 3, 0.9)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should fail if member does not exist", func() {
+			_, err := leaderboards.GetActivityScore(NewEmptyCtx(), leaderboardID, "invalid-member", 0.5)
+			Expect(err).To(Equal(NewMemberNotFound(leaderboardID, "invalid-member")))
+		})
+
+		It("should fail if the recency weight is out of range", func() {
+			_, err := leaderboards.GetActivityScore(NewEmptyCtx(), leaderboardID, "member-a", 1.5)
+			Expect(err).To(HaveOccurred())
+			_, err = leaderboards.GetLeadersByActivityScore(NewEmptyCtx(), leaderboardID, 2, 1, -0.5)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if faulty redis client", func() {
+			_, err := faultyLeaderboards.GetActivityScore(NewEmptyCtx(), leaderboardID, "member-a", 0.5)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+			_, err = faultyLeaderboards.GetLeadersByActivityScore(NewEmptyCtx(), leaderboardID, 2, 1, 0.5)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting score thresholds for top and bottom n", func() {
+		It("should return the score needed to be among the top and bottom n members", func() {
+			leaderboardID := uuid.NewV4().String()
+			for i := 1; i <= 10; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), leaderboardID, "member_"+strconv.Itoa(i), int64(i*100), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			score, err := leaderboards.GetMinScoreForTopN(NewEmptyCtx(), leaderboardID, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(1000)))
+
+			score, err = leaderboards.GetMinScoreForTopN(NewEmptyCtx(), leaderboardID, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(800)))
+
+			score, err = leaderboards.GetMaxScoreForBottomN(NewEmptyCtx(), leaderboardID, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(300)))
+
+			score, err = leaderboards.GetMinScoreForTopN(NewEmptyCtx(), leaderboardID, 11)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(0)))
+
+			score, err = leaderboards.GetMaxScoreForBottomN(NewEmptyCtx(), leaderboardID, 11)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(0)))
+		})
+
+		It("should return 0 if the leaderboard is empty", func() {
+			score, err := leaderboards.GetMinScoreForTopN(NewEmptyCtx(), uuid.NewV4().String(), 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(score).To(Equal(int64(0)))
+		})
+
+		It("should fail if n is not positive", func() {
+			_, err := leaderboards.GetMinScoreForTopN(NewEmptyCtx(), testLeaderboardID, 0)
+			Expect(err).To(HaveOccurred())
+			_, err = leaderboards.GetMaxScoreForBottomN(NewEmptyCtx(), testLeaderboardID, -1)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if faulty redis client", func() {
+			_, err := faultyLeaderboards.GetMinScoreForTopN(NewEmptyCtx(), testLeaderboardID, 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+			_, err = faultyLeaderboards.GetMaxScoreForBottomN(NewEmptyCtx(), testLeaderboardID, 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members with an exact score", func() {
+		var leaderboardID string
+
+		BeforeEach(func() {
+			leaderboardID = uuid.NewV4().String()
+			err := leaderboards.SetMembersScore(NewEmptyCtx(), leaderboardID, Members{
+				&Member{PublicID: "member-e", Score: 300},
+				&Member{PublicID: "member-c", Score: 200},
+				&Member{PublicID: "member-a", Score: 200},
+				&Member{PublicID: "member-b", Score: 200},
+				&Member{PublicID: "member-d", Score: 100},
+			}, false, "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return tied members ranked by public ID", func() {
+			members, err := leaderboards.GetMembersWithScore(NewEmptyCtx(), leaderboardID, 200, 0, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{
+				&Member{PublicID: "member-a", Score: 200, Rank: 2},
+				&Member{PublicID: "member-b", Score: 200, Rank: 3},
+				&Member{PublicID: "member-c", Score: 200, Rank: 4},
+			}))
+
+			members, err = leaderboards.GetMembersWithScore(NewEmptyCtx(), leaderboardID, 200, 2, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{
+				&Member{PublicID: "member-a", Score: 200, Rank: 2},
+				&Member{PublicID: "member-b", Score: 200, Rank: 3},
+			}))
+
+			members, err = leaderboards.GetMembersWithScore(NewEmptyCtx(), leaderboardID, 300, 10, "invalid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{&Member{PublicID: "member-e", Score: 300, Rank: 1}}))
+		})
+
+		It("should return no members if no member has the score", func() {
+			members, err := leaderboards.GetMembersWithScore(NewEmptyCtx(), leaderboardID, 150, 0, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should fail if faulty redis client", func() {
+			_, err := faultyLeaderboards.GetMembersWithScore(NewEmptyCtx(), leaderboardID, 200, 0, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("ranking by weighted metrics", func() {
+		var lbID string
+		var weights []WeightedMetric
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			weights = []WeightedMetric{{Name: "kills", Weight: 2}, {Name: "assists", Weight: 1}, {Name: "deaths", Weight: -1.5}}
+			metrics := map[string]map[string]int64{
+				"member-1": {"kills": 10, "assists": 2, "deaths": 4},
+				"member-2": {"kills": 5, "assists": 20, "deaths": 2},
+				"member-3": {"kills": 12},
+				"member-4": {},
+			}
+			for memberID, values := range metrics {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, 0, false, "")
+				Expect(err).NotTo(HaveOccurred())
+				for metric, value := range values {
+					err = leaderboards.RecordMemberMetric(NewEmptyCtx(), lbID, memberID, metric, value)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+		})
+
+		It("should rank members by the weighted sum of their metrics", func() {
+			members, err := leaderboards.RankByWeightedMetrics(NewEmptyCtx(), lbID, weights, 3, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{
+				&Member{PublicID: "member-2", Score: 27, Rank: 1},
+				&Member{PublicID: "member-3", Score: 24, Rank: 2},
+				&Member{PublicID: "member-1", Score: 16, Rank: 3},
+			}))
+
+			members, err = leaderboards.RankByWeightedMetrics(NewEmptyCtx(), lbID, weights, 3, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{&Member{PublicID: "member-4", Score: 0, Rank: 4}}))
+
+			members, err = leaderboards.RankByWeightedMetrics(NewEmptyCtx(), lbID, weights, 3, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should overwrite previously recorded metrics and not leave temporary keys", func() {
+			err := leaderboards.RecordMemberMetric(NewEmptyCtx(), lbID, "member-4", "kills", 100)
+			Expect(err).NotTo(HaveOccurred())
+			err = leaderboards.RecordMemberMetric(NewEmptyCtx(), lbID, "member-4", "kills", 20)
+			Expect(err).NotTo(HaveOccurred())
+
+			members, err := leaderboards.RankByWeightedMetrics(NewEmptyCtx(), lbID, weights, 1, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{&Member{PublicID: "member-4", Score: 40, Rank: 1}}))
+
+			keys, err := redisClient.Client.Eval(`return redis.call("KEYS", ARGV[1])`, []string{}, lbID+":weighted:*").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(BeEmpty())
+		})
+
+		It("should fail without weights", func() {
+			_, err := leaderboards.RankByWeightedMetrics(NewEmptyCtx(), lbID, []WeightedMetric{}, 3, 1)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.RecordMemberMetric(NewEmptyCtx(), lbID, "member-1", "kills", 1)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.RankByWeightedMetrics(NewEmptyCtx(), lbID, weights, 3, 1)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting member quartile", func() {
+		quartiles := func(lbID string, size int, order string) []int {
+			result := make([]int, size)
+			for i := 1; i <= size; i++ {
+				quartile, err := leaderboards.GetMemberQuartile(NewEmptyCtx(), lbID, "member_"+strconv.Itoa(i), order)
+				Expect(err).NotTo(HaveOccurred())
+				result[i-1] = quartile
+			}
+			return result
+		}
+
+		It("should return the quartile of each member", func() {
+			lbID := uuid.NewV4().String()
+			for i := 1; i <= 8; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member_"+strconv.Itoa(i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(quartiles(lbID, 8, "desc")).To(Equal([]int{1, 1, 2, 2, 3, 3, 4, 4}))
+			Expect(quartiles(lbID, 8, "asc")).To(Equal([]int{4, 4, 3, 3, 2, 2, 1, 1}))
+		})
+
+		It("should return quartiles for leaderboard sizes that are not multiples of four", func() {
+			lbID := uuid.NewV4().String()
+			for i := 1; i <= 7; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member_"+strconv.Itoa(i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(quartiles(lbID, 7, "desc")).To(Equal([]int{1, 1, 2, 2, 3, 3, 4}))
+
+			lbID = uuid.NewV4().String()
+			for i := 1; i <= 5; i++ {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member_"+strconv.Itoa(i), int64(100-i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(quartiles(lbID, 5, "desc")).To(Equal([]int{1, 1, 2, 3, 4}))
+
+			lbID = uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member_1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quartiles(lbID, 1, "desc")).To(Equal([]int{1}))
+		})
+
+		It("should fail if member does not exist", func() {
+			_, err := leaderboards.GetMemberQuartile(NewEmptyCtx(), testLeaderboardID, "invalid-member", "desc")
+			Expect(err).To(Equal(NewMemberNotFound(testLeaderboardID, "invalid-member")))
+		})
+
+		It("should fail if faulty redis client", func() {
+			_, err := faultyLeaderboards.GetMemberQuartile(NewEmptyCtx(), testLeaderboardID, "member_1", "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("transactional set of members score", func() {
+		It("should set the score of all members", func() {
+			lbID := uuid.NewV4().String()
+			members := Members{
+				&Member{PublicID: "member-1", Score: 100},
+				&Member{PublicID: "member-2", Score: 200},
+			}
+			err := leaderboards.SetMembersScoreTransactional(NewEmptyCtx(), lbID, members, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members[0].Rank).To(Equal(2))
+			Expect(members[1].Rank).To(Equal(1))
+
+			leaders, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 10, 1, "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(leaders).To(HaveLen(2))
+			Expect(leaders[0].PublicID).To(Equal("member-2"))
+			Expect(leaders[0].Score).To(Equal(int64(200)))
+		})
+
+		It("should not update any member if one of them changed after being validated", func() {
+			lbID := uuid.NewV4().String()
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			racingLeaderboards := NewClientWithRedis(redisClient, WithScoreValidator(
+				func(memberID string, currentScore, proposedScore int64) error {
+					if memberID == "member-2" {
+						return redisClient.Client.ZAdd(lbID, redis.Z{Score: 999, Member: "member-2"}).Err()
+					}
+					return nil
+				}))
+
+			err = racingLeaderboards.SetMembersScoreTransactional(NewEmptyCtx(), lbID, Members{
+				&Member{PublicID: "member-1", Score: 150},
+				&Member{PublicID: "member-2", Score: 200},
+				&Member{PublicID: "member-3", Score: 300},
+			}, false, "")
+			Expect(err).To(Equal(&TransactionError{LeaderboardID: lbID, MemberID: "member-2", Err: ErrScoreChanged}))
+
+			leaders, err := leaderboards.GetLeaders(NewEmptyCtx(), lbID, 10, 1, "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(leaders).To(Equal([]*Member{
+				&Member{PublicID: "member-2", Score: 999, Rank: 1},
+				&Member{PublicID: "member-1", Score: 100, Rank: 2},
+			}))
+		})
+
+		It("should not update any member if one of them is rejected by the score validator", func() {
+			lbID := uuid.NewV4().String()
+			validatedLeaderboards := NewClientWithRedis(redisClient, WithScoreValidator(
+				func(memberID string, currentScore, proposedScore int64) error {
+					if proposedScore < 0 {
+						return fmt.Errorf("negative score")
+					}
+					return nil
+				}))
+
+			err := validatedLeaderboards.SetMembersScoreTransactional(NewEmptyCtx(), lbID, Members{
+				&Member{PublicID: "member-1", Score: 100},
+				&Member{PublicID: "member-2", Score: -1},
+			}, false, "")
+			Expect(err).To(Equal(&TransactionError{LeaderboardID: lbID, MemberID: "member-2", Err: fmt.Errorf("negative score")}))
+
+			total, err := leaderboards.TotalMembers(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(0))
+		})
+
+		It("should fail if a member is updated more than once", func() {
+			lbID := uuid.NewV4().String()
+			err := leaderboards.SetMembersScoreTransactional(NewEmptyCtx(), lbID, Members{
+				&Member{PublicID: "member-1", Score: 100},
+				&Member{PublicID: "member-1", Score: 200},
+			}, false, "")
+			txErr, ok := err.(*TransactionError)
+			Expect(ok).To(BeTrue())
+			Expect(txErr.MemberID).To(Equal("member-1"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.SetMembersScoreTransactional(NewEmptyCtx(), testLeaderboardID, Members{
+				&Member{PublicID: "member-1", Score: 100},
+			}, false, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("leaderboard metadata", func() {
+		It("should set and get the leaderboard metadata", func() {
+			lbID := uuid.NewV4().String()
+			meta, err := leaderboards.GetLeaderboardMeta(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta).To(BeEmpty())
+
+			err = leaderboards.SetLeaderboardMeta(NewEmptyCtx(), lbID, map[string]string{"gameMode": "battle_royale", "region": "us-east"})
+			Expect(err).NotTo(HaveOccurred())
+			err = leaderboards.SetLeaderboardMetaField(NewEmptyCtx(), lbID, "region", "eu-west")
+			Expect(err).NotTo(HaveOccurred())
+
+			meta, err = leaderboards.GetLeaderboardMeta(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta).To(Equal(map[string]string{"gameMode": "battle_royale", "region": "eu-west"}))
+
+			err = leaderboards.SetLeaderboardMeta(NewEmptyCtx(), lbID, map[string]string{"season": "3"})
+			Expect(err).NotTo(HaveOccurred())
+			meta, err = leaderboards.GetLeaderboardMeta(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta).To(Equal(map[string]string{"season": "3"}))
+		})
+
+		It("should fail to set reserved keys", func() {
+			lbID := uuid.NewV4().String()
+			err := leaderboards.SetLeaderboardMeta(NewEmptyCtx(), lbID, map[string]string{"region": "us-east", "_internal_version": "1"})
+			Expect(err).To(HaveOccurred())
+			err = leaderboards.SetLeaderboardMetaField(NewEmptyCtx(), lbID, "_internal_version", "1")
+			Expect(err).To(HaveOccurred())
+			err = leaderboards.SetLeaderboardMetaField(NewEmptyCtx(), lbID, "", "1")
+			Expect(err).To(HaveOccurred())
+
+			meta, err := leaderboards.GetLeaderboardMeta(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(meta).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.SetLeaderboardMeta(NewEmptyCtx(), testLeaderboardID, map[string]string{"region": "us-east"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			err = faultyLeaderboards.SetLeaderboardMetaField(NewEmptyCtx(), testLeaderboardID, "region", "us-east")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetLeaderboardMeta(NewEmptyCtx(), testLeaderboardID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("leaderboard change log", func() {
+		It("should record every score change when enabled", func() {
+			lbID := uuid.NewV4().String()
+			changeLogLeaderboards := NewClientWithRedis(redisClient, WithChangeLog())
+			_, err := changeLogLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = changeLogLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 50, "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 10, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, err := changeLogLeaderboards.GetChangeLogs(NewEmptyCtx(), lbID, "", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].MemberID).To(Equal("member-1"))
+			Expect(entries[0].OldScore).To(BeNil())
+			Expect(entries[0].NewScore).To(Equal(int64(100)))
+			Expect(entries[0].Operation).To(Equal("set"))
+			Expect(entries[0].Timestamp).To(BeNumerically("~", time.Now().Unix(), 5))
+			Expect(*entries[1].OldScore).To(Equal(int64(100)))
+			Expect(entries[1].NewScore).To(Equal(int64(150)))
+			Expect(entries[1].Operation).To(Equal("increment"))
+
+			entries, err = changeLogLeaderboards.GetChangeLogs(NewEmptyCtx(), lbID, entries[1].ID, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].NewScore).To(Equal(int64(150)))
+		})
+
+		It("should expire the change log with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			changeLogLeaderboards := NewClientWithRedis(redisClient, WithChangeLog())
+			_, err := changeLogLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			ttl, err := redisClient.Client.TTL(lbID + ":changelog").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+		})
+
+		It("should trim the change log", func() {
+			lbID := uuid.NewV4().String()
+			changeLogLeaderboards := NewClientWithRedis(redisClient)
+			changeLogLeaderboards.SetChangeLogEnabled(true)
+			Expect(changeLogLeaderboards.ChangeLogEnabled()).To(BeTrue())
+			for i := 1; i <= 5; i++ {
+				_, err := changeLogLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", int64(i), false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err := changeLogLeaderboards.TrimChangeLog(NewEmptyCtx(), lbID, 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, err := changeLogLeaderboards.GetChangeLogs(NewEmptyCtx(), lbID, "", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].NewScore).To(Equal(int64(4)))
+			Expect(entries[1].NewScore).To(Equal(int64(5)))
+
+			entries, err = changeLogLeaderboards.GetChangeLogs(NewEmptyCtx(), lbID, "", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+
+		It("should return no entries if the change log is empty", func() {
+			entries, err := leaderboards.GetChangeLogs(NewEmptyCtx(), uuid.NewV4().String(), "", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetChangeLogs(NewEmptyCtx(), testLeaderboardID, "", 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			err = faultyLeaderboards.TrimChangeLog(NewEmptyCtx(), testLeaderboardID, 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members for a join cohort", func() {
+		var lbID string
+		var weekStart time.Time
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			weekStart = time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)
+			joined := map[string]time.Time{
+				"member-1": weekStart.Add(-time.Hour),
+				"member-2": weekStart,
+				"member-3": weekStart.Add(3 * 24 * time.Hour),
+				"member-4": weekStart.Add(6 * 24 * time.Hour),
+				"member-5": weekStart.Add(7 * 24 * time.Hour),
+				"member-6": weekStart.Add(24 * time.Hour),
+			}
+			for i := 1; i <= 6; i++ {
+				memberID := fmt.Sprintf("member-%d", i)
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, int64(i*10), false, "")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = redisClient.Client.ZAdd(lbID+":joined", redis.Z{Score: float64(joined[memberID].Unix()), Member: memberID}).Result()
+				Expect(err).NotTo(HaveOccurred())
+			}
+			err := leaderboards.RemoveMember(NewEmptyCtx(), lbID, "member-6")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the members that joined in the cohort window sorted by rank", func() {
+			weekEnd := weekStart.Add(7 * 24 * time.Hour)
+			members, err := leaderboards.GetMembersForCohort(NewEmptyCtx(), lbID, weekStart, weekEnd, 10, 1, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{
+				&Member{PublicID: "member-4", Score: 40, Rank: 2},
+				&Member{PublicID: "member-3", Score: 30, Rank: 3},
+				&Member{PublicID: "member-2", Score: 20, Rank: 4},
+			}))
+
+			members, err = leaderboards.GetMembersForCohort(NewEmptyCtx(), lbID, weekStart, weekEnd, 2, 2, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(Equal([]*Member{&Member{PublicID: "member-4", Score: 40, Rank: 4}}))
+
+			members, err = leaderboards.GetMembersForCohort(NewEmptyCtx(), lbID, weekStart, weekEnd, 2, 3, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(BeEmpty())
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersForCohort(NewEmptyCtx(), lbID, weekStart, time.Now(), 10, 1, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting comparison matrix", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			err := leaderboards.SetMembersScore(NewEmptyCtx(), lbID, Members{
+				&Member{PublicID: "a-1", Score: 100},
+				&Member{PublicID: "a-2", Score: 50},
+				&Member{PublicID: "b-1", Score: 80},
+				&Member{PublicID: "b-2", Score: 50},
+				&Member{PublicID: "b-3", Score: 10},
+			}, false, "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should compare every member of both teams", func() {
+			matrix, err := leaderboards.GetComparisonMatrix(NewEmptyCtx(), lbID, []string{"a-1", "a-2"}, []string{"b-1", "b-2", "b-3"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matrix).To(Equal(&ComparisonMatrix{
+				TeamA:       []string{"a-1", "a-2"},
+				TeamB:       []string{"b-1", "b-2", "b-3"},
+				Differences: [][]int64{{20, 50, 90}, {-30, 0, 40}},
+				TeamAWins:   4,
+				TeamBWins:   1,
+				Draws:       1,
+			}))
+		})
+
+		It("should return an empty matrix for empty teams", func() {
+			matrix, err := leaderboards.GetComparisonMatrix(NewEmptyCtx(), lbID, []string{}, []string{"b-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matrix.Differences).To(BeEmpty())
+			Expect(matrix.TeamAWins + matrix.TeamBWins + matrix.Draws).To(Equal(0))
+		})
+
+		It("should fail if a member is not in the leaderboard", func() {
+			_, err := leaderboards.GetComparisonMatrix(NewEmptyCtx(), lbID, []string{"a-1"}, []string{"b-1", "invalid-member"})
+			Expect(err).To(Equal(NewMemberNotFound(lbID, "invalid-member")))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetComparisonMatrix(NewEmptyCtx(), lbID, []string{"a-1"}, []string{"b-1"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting member by alias", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			err := leaderboards.SetMembersScore(NewEmptyCtx(), lbID, Members{
+				&Member{PublicID: "account-1", Score: 100},
+				&Member{PublicID: "account-2", Score: 50},
+			}, false, "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the member registered under the alias", func() {
+			err := leaderboards.RegisterMemberAlias(NewEmptyCtx(), lbID, "account-2", "device-2")
+			Expect(err).NotTo(HaveOccurred())
+
+			member, err := leaderboards.GetMemberByAlias(NewEmptyCtx(), lbID, "device-2", "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.PublicID).To(Equal("account-2"))
+			Expect(member.Score).To(Equal(int64(50)))
+			Expect(member.Rank).To(Equal(2))
+		})
+
+		It("should replace the mapping when the alias is registered again", func() {
+			err := leaderboards.RegisterMemberAlias(NewEmptyCtx(), lbID, "account-2", "facebook-1")
+			Expect(err).NotTo(HaveOccurred())
+			err = leaderboards.RegisterMemberAlias(NewEmptyCtx(), lbID, "account-1", "facebook-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			member, err := leaderboards.GetMemberByAlias(NewEmptyCtx(), lbID, "facebook-1", "asc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.PublicID).To(Equal("account-1"))
+			Expect(member.Rank).To(Equal(2))
+		})
+
+		It("should fail if the alias is not registered", func() {
+			_, err := leaderboards.GetMemberByAlias(NewEmptyCtx(), lbID, "device-1", "desc", false)
+			Expect(err).To(Equal(NewAliasNotFound(lbID, "device-1")))
+		})
+
+		It("should fail if the aliased member is not in the leaderboard", func() {
+			err := leaderboards.RegisterMemberAlias(NewEmptyCtx(), lbID, "account-3", "device-3")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.GetMemberByAlias(NewEmptyCtx(), lbID, "device-3", "desc", false)
+			Expect(err).To(Equal(NewMemberNotFound(lbID, "account-3")))
+		})
+
+		It("should be removed with the leaderboard family", func() {
+			err := leaderboards.RegisterMemberAlias(NewEmptyCtx(), lbID, "account-1", "device-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.DeleteLeaderboardFamily(NewEmptyCtx(), lbID)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = leaderboards.GetMemberByAlias(NewEmptyCtx(), lbID, "device-1", "desc", false)
+			Expect(err).To(Equal(NewAliasNotFound(lbID, "device-1")))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			err := faultyLeaderboards.RegisterMemberAlias(NewEmptyCtx(), lbID, "account-1", "device-1")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetMemberByAlias(NewEmptyCtx(), lbID, "device-1", "desc", false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("leaderboard write throughput", func() {
+		addChangeLogEntry := func(lbID, id string) {
+			_, err := redisClient.Client.Eval(
+				`return redis.call("XADD", KEYS[1], ARGV[1], "memberID", "member")`,
+				[]string{fmt.Sprintf("%s:changelog", lbID)}, id,
+			).Result()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should return the writes per second during the window", func() {
+			lbID := uuid.NewV4().String()
+			addChangeLogEntry(lbID, "1-0")
+			changeLogLeaderboards := NewClientWithRedis(redisClient, WithChangeLog())
+			for i := 0; i < 5; i++ {
+				_, err := changeLogLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 10, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			throughput, err := changeLogLeaderboards.GetWriteThroughput(NewEmptyCtx(), lbID, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(throughput).To(Equal(0.5))
+		})
+
+		It("should return zero if there were no writes", func() {
+			throughput, err := leaderboards.GetWriteThroughput(NewEmptyCtx(), uuid.NewV4().String(), time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(throughput).To(Equal(0.0))
+		})
+
+		It("should fail if the window is too small", func() {
+			_, err := leaderboards.GetWriteThroughput(NewEmptyCtx(), uuid.NewV4().String(), time.Microsecond)
+			Expect(err).To(MatchError("Window must be at least one millisecond"))
+		})
+
+		It("should return the highest number of writes within a second", func() {
+			lbID := uuid.NewV4().String()
+			for _, id := range []string{"500-0", "1000-0", "1500-0", "1500-1", "1999-0", "2000-0", "2001-0"} {
+				addChangeLogEntry(lbID, id)
+			}
+
+			peak, err := leaderboards.GetPeakWriteThroughput(NewEmptyCtx(), lbID, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(peak).To(Equal(4.0))
+
+			peak, err = leaderboards.GetPeakWriteThroughput(NewEmptyCtx(), lbID, "1500-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(peak).To(Equal(2.0))
+		})
+
+		It("should scan change logs larger than a batch", func() {
+			lbID := uuid.NewV4().String()
+			for i := 0; i < 1010; i++ {
+				addChangeLogEntry(lbID, fmt.Sprintf("%d-%d", 1000+i/1000*1000, i%1000))
+			}
+
+			peak, err := leaderboards.GetPeakWriteThroughput(NewEmptyCtx(), lbID, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(peak).To(Equal(1000.0))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetWriteThroughput(NewEmptyCtx(), uuid.NewV4().String(), time.Minute)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+			_, err = faultyLeaderboards.GetPeakWriteThroughput(NewEmptyCtx(), uuid.NewV4().String(), "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting members for reward tiers", func() {
+		var lbID string
+
+		BeforeEach(func() {
+			lbID = uuid.NewV4().String()
+			members := Members{}
+			for i := 1; i <= 6; i++ {
+				members = append(members, &Member{PublicID: fmt.Sprintf("member_%d", i), Score: int64(100 * i)})
+			}
+			err := leaderboards.SetMembersScore(NewEmptyCtx(), lbID, members, false, "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the members in each tier", func() {
+			members, err := leaderboards.GetMembersForReward(NewEmptyCtx(), lbID, []RewardTier{
+				{Name: "silver", MinRank: 2, MaxRank: 3},
+				{Name: "gold", MinRank: 1, MaxRank: 1},
+				{Name: "bronze", MinRank: 4, MaxRank: 10},
+			}, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members).To(HaveLen(3))
+			Expect(members["gold"]).To(Equal([]*Member{{PublicID: "member_6", Score: 600, Rank: 1}}))
+			Expect(members["silver"]).To(Equal([]*Member{
+				{PublicID: "member_5", Score: 500, Rank: 2},
+				{PublicID: "member_4", Score: 400, Rank: 3},
+			}))
+			Expect(members["bronze"]).To(HaveLen(3))
+			Expect(members["bronze"][2]).To(Equal(&Member{PublicID: "member_1", Score: 100, Rank: 6}))
+		})
+
+		It("should return the members in each tier in ascending order", func() {
+			members, err := leaderboards.GetMembersForReward(NewEmptyCtx(), lbID, []RewardTier{
+				{Name: "first", MinRank: 1, MaxRank: 2},
+				{Name: "second", MinRank: 3, MaxRank: 3},
+			}, "asc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members["first"]).To(Equal([]*Member{
+				{PublicID: "member_1", Score: 100, Rank: 1},
+				{PublicID: "member_2", Score: 200, Rank: 2},
+			}))
+			Expect(members["second"]).To(Equal([]*Member{{PublicID: "member_3", Score: 300, Rank: 3}}))
+		})
+
+		It("should return an empty list for tiers past the end of the leaderboard", func() {
+			members, err := leaderboards.GetMembersForReward(NewEmptyCtx(), lbID, []RewardTier{
+				{Name: "consolation", MinRank: 10, MaxRank: 20},
+			}, "desc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(members["consolation"]).To(BeEmpty())
+		})
+
+		It("should fail if tiers overlap", func() {
+			_, err := leaderboards.GetMembersForReward(NewEmptyCtx(), lbID, []RewardTier{
+				{Name: "gold", MinRank: 1, MaxRank: 2},
+				{Name: "silver", MinRank: 2, MaxRank: 5},
+			}, "desc")
+			Expect(err).To(MatchError("Reward tiers gold and silver overlap"))
+		})
+
+		It("should fail if tiers are not contiguous", func() {
+			_, err := leaderboards.GetMembersForReward(NewEmptyCtx(), lbID, []RewardTier{
+				{Name: "gold", MinRank: 1, MaxRank: 1},
+				{Name: "silver", MinRank: 3, MaxRank: 5},
+			}, "desc")
+			Expect(err).To(MatchError("Reward tiers gold and silver are not contiguous"))
+		})
+
+		It("should fail if a tier has an invalid rank range", func() {
+			_, err := leaderboards.GetMembersForReward(NewEmptyCtx(), lbID, []RewardTier{
+				{Name: "gold", MinRank: 3, MaxRank: 1},
+			}, "desc")
+			Expect(err).To(MatchError("Reward tier gold has invalid rank range 3-1"))
+		})
+
+		It("should fail if a tier name is duplicated", func() {
+			_, err := leaderboards.GetMembersForReward(NewEmptyCtx(), lbID, []RewardTier{
+				{Name: "gold", MinRank: 1, MaxRank: 1},
+				{Name: "gold", MinRank: 2, MaxRank: 2},
+			}, "desc")
+			Expect(err).To(MatchError("Reward tier gold is duplicated"))
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetMembersForReward(NewEmptyCtx(), lbID, []RewardTier{
+				{Name: "gold", MinRank: 1, MaxRank: 1},
+			}, "desc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
+
+	Describe("getting score cardinality", func() {
+		It("should count distinct scores", func() {
+			lbID := uuid.NewV4().String()
+			members := Members{}
+			for i := 0; i < 2500; i++ {
+				members = append(members, &Member{PublicID: fmt.Sprintf("member_%d", i), Score: int64(i % 7)})
+			}
+			err := leaderboards.SetMembersScore(NewEmptyCtx(), lbID, members, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			cardinality, err := leaderboards.GetScoreCardinality(NewEmptyCtx(), lbID, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cardinality).To(Equal(int64(7)))
+		})
+
+		It("should estimate distinct scores", func() {
+			lbID := uuid.NewV4().String()
+			members := Members{}
+			for i := 0; i < 2500; i++ {
+				members = append(members, &Member{PublicID: fmt.Sprintf("member_%d", i), Score: int64(i / 2)})
+			}
+			cardinalityLeaderboards := NewClientWithRedis(redisClient, WithScoreCardinality())
+			err := cardinalityLeaderboards.SetMembersScore(NewEmptyCtx(), lbID, members, false, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			cardinality, err := cardinalityLeaderboards.GetScoreCardinality(NewEmptyCtx(), lbID, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cardinality).To(BeNumerically("~", 1250, 50))
+		})
+
+		It("should only keep the score cardinality if enabled and expire it with the leaderboard", func() {
+			lbID := fmt.Sprintf("%s-year%d", uuid.NewV4().String(), time.Now().UTC().Year())
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+			Expect(err).NotTo(HaveOccurred())
+			cardinality, err := leaderboards.GetScoreCardinality(NewEmptyCtx(), lbID, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cardinality).To(Equal(int64(0)))
+
+			cardinalityLeaderboards := NewClientWithRedis(redisClient, WithScoreCardinality())
+			_, err = cardinalityLeaderboards.IncrementMemberScore(NewEmptyCtx(), lbID, "member-1", 10, "")
+			Expect(err).NotTo(HaveOccurred())
+			cardinality, err = cardinalityLeaderboards.GetScoreCardinality(NewEmptyCtx(), lbID, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cardinality).To(Equal(int64(1)))
+
+			ttl, err := redisClient.Client.TTL(fmt.Sprintf("%s:cardinality", lbID)).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(BeNumerically(">", 0))
+		})
+
+		It("should return zero for an empty leaderboard", func() {
+			lbID := uuid.NewV4().String()
+			for _, approximate := range []bool{false, true} {
+				cardinality, err := leaderboards.GetScoreCardinality(NewEmptyCtx(), lbID, approximate)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cardinality).To(Equal(int64(0)))
+			}
+		})
+
+		It("should fail if invalid connection to Redis", func() {
+			_, err := faultyLeaderboards.GetScoreCardinality(NewEmptyCtx(), uuid.NewV4().String(), false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("connection refused"))
+		})
+	})
 })
@@ -0,0 +1,89 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-redis/redis"
+)
+
+// registryKey is the Redis hash mapping external IDs to leaderboard IDs
+const registryKey = "podium:registry"
+
+// ExternalIDNotFoundError indicates no leaderboard is registered with an external ID
+type ExternalIDNotFoundError struct {
+	ExternalID string
+}
+
+func (e *ExternalIDNotFoundError) Error() string {
+	return fmt.Sprintf("Could not find leaderboard registered with external ID %s.", e.ExternalID)
+}
+
+// LeaderboardRegistry maps opaque external IDs, such as match IDs, to leaderboard IDs.
+// The mapping is kept in Redis, so it is shared by every process and safe for concurrent use.
+type LeaderboardRegistry struct {
+	client *Client
+}
+
+// NewLeaderboardRegistry returns a registry stored in the Redis used by the given client
+func NewLeaderboardRegistry(client *Client) *LeaderboardRegistry {
+	return &LeaderboardRegistry{client: client}
+}
+
+// Register maps an external ID to a leaderboard, replacing any previous mapping of that external ID
+func (r *LeaderboardRegistry) Register(ctx context.Context, externalID string, leaderboardID string) error {
+	_, err := r.client.redisWithTracing(ctx).HSet(registryKey, externalID, leaderboardID).Result()
+	if err != nil {
+		return fmt.Errorf("Registering leaderboard failed: %v", err)
+	}
+	return nil
+}
+
+// Lookup returns the ID of the leaderboard registered with an external ID
+func (r *LeaderboardRegistry) Lookup(ctx context.Context, externalID string) (string, error) {
+	leaderboardID, err := r.client.redisWithTracing(ctx).HGet(registryKey, externalID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", &ExternalIDNotFoundError{ExternalID: externalID}
+		}
+		return "", fmt.Errorf("Looking up leaderboard failed: %v", err)
+	}
+	return leaderboardID, nil
+}
+
+// Deregister removes the mapping of an external ID
+func (r *LeaderboardRegistry) Deregister(ctx context.Context, externalID string) error {
+	removed, err := r.client.redisWithTracing(ctx).HDel(registryKey, externalID).Result()
+	if err != nil {
+		return fmt.Errorf("Deregistering leaderboard failed: %v", err)
+	}
+	if removed == 0 {
+		return &ExternalIDNotFoundError{ExternalID: externalID}
+	}
+	return nil
+}
+
+// ListRegistered returns all registered external IDs, sorted
+func (r *LeaderboardRegistry) ListRegistered(ctx context.Context) ([]string, error) {
+	registry, err := r.client.redisWithTracing(ctx).HGetAll(registryKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Listing registered leaderboards failed: %v", err)
+	}
+
+	externalIDs := make([]string, 0, len(registry))
+	for externalID := range registry {
+		externalIDs = append(externalIDs, externalID)
+	}
+	sort.Strings(externalIDs)
+	return externalIDs, nil
+}
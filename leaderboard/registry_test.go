@@ -0,0 +1,86 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	redis "github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Leaderboard Registry", func() {
+	var registry *LeaderboardRegistry
+	var faultyRegistry *LeaderboardRegistry
+
+	BeforeEach(func() {
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = redisClient.Client.Del("podium:registry").Result()
+		Expect(err).NotTo(HaveOccurred())
+		registry = NewLeaderboardRegistry(NewClientWithRedis(redisClient))
+
+		faultyRedisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		faultyRedisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		faultyRegistry = NewLeaderboardRegistry(NewClientWithRedis(faultyRedisClient))
+	})
+
+	It("should register and look up leaderboards", func() {
+		lbID := uuid.NewV4().String()
+		err := registry.Register(NewEmptyCtx(), "match-1", lbID)
+		Expect(err).NotTo(HaveOccurred())
+
+		leaderboardID, err := registry.Lookup(NewEmptyCtx(), "match-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(leaderboardID).To(Equal(lbID))
+	})
+
+	It("should list registered external IDs", func() {
+		Expect(registry.Register(NewEmptyCtx(), "match-2", "lb-2")).To(Succeed())
+		Expect(registry.Register(NewEmptyCtx(), "match-1", "lb-1")).To(Succeed())
+
+		externalIDs, err := registry.ListRegistered(NewEmptyCtx())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(externalIDs).To(Equal([]string{"match-1", "match-2"}))
+	})
+
+	It("should deregister external IDs", func() {
+		Expect(registry.Register(NewEmptyCtx(), "match-1", "lb-1")).To(Succeed())
+
+		err := registry.Deregister(NewEmptyCtx(), "match-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = registry.Lookup(NewEmptyCtx(), "match-1")
+		Expect(err).To(BeAssignableToTypeOf(&ExternalIDNotFoundError{}))
+
+		err = registry.Deregister(NewEmptyCtx(), "match-1")
+		Expect(err).To(BeAssignableToTypeOf(&ExternalIDNotFoundError{}))
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		err := faultyRegistry.Register(NewEmptyCtx(), "match-1", "lb-1")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+
+		_, err = faultyRegistry.Lookup(NewEmptyCtx(), "match-1")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
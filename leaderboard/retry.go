@@ -0,0 +1,297 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/topfreegames/podium/log"
+	"go.uber.org/zap"
+)
+
+// retriableRedisErrors are the prefixes of the redis error replies sent while the server can not serve
+// the command yet, they are safe to retry for every operation
+var retriableRedisErrors = []string{
+	"LOADING ",
+	"BUSYGROUP ",
+}
+
+// isRetriable tells whether a failed operation is worth retrying. Failures to connect are always retried
+// since the command never reached redis. Other network errors and unexpected EOFs are ambiguous, the
+// command may have been applied before the connection broke, so they are only retried for idempotent
+// operations. The wrapped client methods keep the redis error as cause with %w.
+func isRetriable(err error, idempotent bool) bool {
+	var opError *net.OpError
+	if errors.As(err, &opError) && opError.Op == "dial" {
+		return true
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return idempotent
+	}
+
+	for unwrapped := errors.Unwrap(err); unwrapped != nil; unwrapped = errors.Unwrap(err) {
+		err = unwrapped
+	}
+	for _, prefix := range retriableRedisErrors {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryMiddleware wraps a Client retrying operations that fail with transient errors using a jittered
+// exponential backoff. Like TimingMiddleware, it only exposes the retried operations: GetMembersByRange,
+// IncrementMemberScore, SetMemberScore, SetMembersScore, TotalMembers, RemoveMembers, RemoveMember,
+// TotalPages, GetMember, GetMembers, GetAroundMe, GetAroundScore, GetRank, GetLeaders, GetTopPercentage,
+// RemoveLeaderboard and Ping. Other operations must be called on the wrapped client and are not retried.
+type RetryMiddleware struct {
+	client     *Client
+	maxRetries int
+	baseDelay  time.Duration
+	logger     zap.Logger
+}
+
+// NewRetryLeaderboard returns a RetryMiddleware that wraps the given client. Each operation is retried at
+// most maxRetries times, waiting a random delay between half and all of baseDelay * 2^attempt in between.
+func NewRetryLeaderboard(client *Client, maxRetries int, baseDelay time.Duration,
+	logger zap.Logger) *RetryMiddleware {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	return &RetryMiddleware{
+		client:     client,
+		maxRetries: maxRetries,
+		baseDelay:  baseDelay,
+		logger:     logger.With(zap.String("source", "leaderboard"), zap.String("operation", "retry")),
+	}
+}
+
+func (r *RetryMiddleware) backoff(attempt int) time.Duration {
+	delay := r.baseDelay << uint(attempt)
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// retry runs fn until it succeeds, fails with an error that is not retriable or runs out of attempts.
+// idempotent tells whether fn can be safely run again after an ambiguous failure, see isRetriable.
+func (r *RetryMiddleware) retry(ctx context.Context, operation string, idempotent bool, fn func() error) error {
+	err := fn()
+	for attempt := 1; attempt <= r.maxRetries && err != nil && isRetriable(err, idempotent); attempt++ {
+		delay := r.backoff(attempt - 1)
+		log.W(r.logger, "Retrying leaderboard operation.", func(cm log.CM) {
+			cm.Write(
+				zap.String("leaderboardOperation", operation),
+				zap.Int("attempt", attempt),
+				zap.Duration("delay", delay),
+				zap.Error(err),
+			)
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		err = fn()
+	}
+	return err
+}
+
+// GetMembersByRange is a retried Client.GetMembersByRange
+func (r *RetryMiddleware) GetMembersByRange(ctx context.Context, leaderboard string, startOffset int, endOffset int,
+	order string) ([]*Member, error) {
+	var members []*Member
+	err := r.retry(ctx, "GetMembersByRange", true, func() (err error) {
+		members, err = r.client.GetMembersByRange(ctx, leaderboard, startOffset, endOffset, order)
+		return err
+	})
+	return members, err
+}
+
+// IncrementMemberScore is a retried Client.IncrementMemberScore. Increments are not idempotent so they are
+// only retried when redis could not be reached at all.
+func (r *RetryMiddleware) IncrementMemberScore(ctx context.Context, leaderboardID string, memberID string,
+	increment int, scoreTTL string) (*Member, error) {
+	var member *Member
+	err := r.retry(ctx, "IncrementMemberScore", false, func() (err error) {
+		member, err = r.client.IncrementMemberScore(ctx, leaderboardID, memberID, increment, scoreTTL)
+		return err
+	})
+	return member, err
+}
+
+// setsAreIdempotent tells whether setting a score twice has the same effect as setting it once. It does not
+// when the write is recorded somewhere else: a repeated set resets the streak, since the score did not
+// improve, and duplicates the score history, the change log and the heatmap counts.
+func (r *RetryMiddleware) setsAreIdempotent() bool {
+	return !r.client.StreakEnabled() && !r.client.ScoreHistoryEnabled() && !r.client.ChangeLogEnabled() &&
+		!r.client.HeatmapEnabled()
+}
+
+// SetMemberScore is a retried Client.SetMemberScore. It is only retried after ambiguous failures if sets are
+// idempotent, see setsAreIdempotent.
+func (r *RetryMiddleware) SetMemberScore(ctx context.Context, leaderboardID string, memberID string, score int64,
+	prevRank bool, scoreTTL string) (*Member, error) {
+	var member *Member
+	err := r.retry(ctx, "SetMemberScore", r.setsAreIdempotent(), func() (err error) {
+		member, err = r.client.SetMemberScore(ctx, leaderboardID, memberID, score, prevRank, scoreTTL)
+		return err
+	})
+	return member, err
+}
+
+// SetMembersScore is a retried Client.SetMembersScore. It is only retried after ambiguous failures if sets are
+// idempotent, see setsAreIdempotent.
+func (r *RetryMiddleware) SetMembersScore(ctx context.Context, leaderboardID string, members Members, prevRank bool,
+	scoreTTL string) error {
+	return r.retry(ctx, "SetMembersScore", r.setsAreIdempotent(), func() error {
+		return r.client.SetMembersScore(ctx, leaderboardID, members, prevRank, scoreTTL)
+	})
+}
+
+// TotalMembers is a retried Client.TotalMembers
+func (r *RetryMiddleware) TotalMembers(ctx context.Context, leaderboardID string) (int, error) {
+	var total int
+	err := r.retry(ctx, "TotalMembers", true, func() (err error) {
+		total, err = r.client.TotalMembers(ctx, leaderboardID)
+		return err
+	})
+	return total, err
+}
+
+// RemoveMembers is a retried Client.RemoveMembers
+func (r *RetryMiddleware) RemoveMembers(ctx context.Context, leaderboardID string, memberIDs []interface{}) error {
+	return r.retry(ctx, "RemoveMembers", true, func() error {
+		return r.client.RemoveMembers(ctx, leaderboardID, memberIDs)
+	})
+}
+
+// RemoveMember is a retried Client.RemoveMember
+func (r *RetryMiddleware) RemoveMember(ctx context.Context, leaderboardID string, memberID string) error {
+	return r.retry(ctx, "RemoveMember", true, func() error {
+		return r.client.RemoveMember(ctx, leaderboardID, memberID)
+	})
+}
+
+// TotalPages is a retried Client.TotalPages
+func (r *RetryMiddleware) TotalPages(ctx context.Context, leaderboardID string, pageSize int) (int, error) {
+	var pages int
+	err := r.retry(ctx, "TotalPages", true, func() (err error) {
+		pages, err = r.client.TotalPages(ctx, leaderboardID, pageSize)
+		return err
+	})
+	return pages, err
+}
+
+// GetMember is a retried Client.GetMember
+func (r *RetryMiddleware) GetMember(ctx context.Context, leaderboardID string, memberID string, order string,
+	includeTTL bool) (*Member, error) {
+	var member *Member
+	err := r.retry(ctx, "GetMember", true, func() (err error) {
+		member, err = r.client.GetMember(ctx, leaderboardID, memberID, order, includeTTL)
+		return err
+	})
+	return member, err
+}
+
+// GetMembers is a retried Client.GetMembers
+func (r *RetryMiddleware) GetMembers(ctx context.Context, leaderboardID string, memberIDs []string, order string,
+	includeTTL bool, includePreviousRank bool) ([]*Member, error) {
+	var members []*Member
+	err := r.retry(ctx, "GetMembers", true, func() (err error) {
+		members, err = r.client.GetMembers(ctx, leaderboardID, memberIDs, order, includeTTL, includePreviousRank)
+		return err
+	})
+	return members, err
+}
+
+// GetAroundMe is a retried Client.GetAroundMe
+func (r *RetryMiddleware) GetAroundMe(ctx context.Context, leaderboardID string, pageSize int, memberID string,
+	order string, getLastIfNotFound bool) ([]*Member, error) {
+	var members []*Member
+	err := r.retry(ctx, "GetAroundMe", true, func() (err error) {
+		members, err = r.client.GetAroundMe(ctx, leaderboardID, pageSize, memberID, order, getLastIfNotFound)
+		return err
+	})
+	return members, err
+}
+
+// GetAroundScore is a retried Client.GetAroundScore
+func (r *RetryMiddleware) GetAroundScore(ctx context.Context, leaderboardID string, pageSize int, score int64,
+	order string) ([]*Member, error) {
+	var members []*Member
+	err := r.retry(ctx, "GetAroundScore", true, func() (err error) {
+		members, err = r.client.GetAroundScore(ctx, leaderboardID, pageSize, score, order)
+		return err
+	})
+	return members, err
+}
+
+// GetRank is a retried Client.GetRank
+func (r *RetryMiddleware) GetRank(ctx context.Context, leaderboardID string, memberID string, order string) (int, error) {
+	var rank int
+	err := r.retry(ctx, "GetRank", true, func() (err error) {
+		rank, err = r.client.GetRank(ctx, leaderboardID, memberID, order)
+		return err
+	})
+	return rank, err
+}
+
+// GetLeaders is a retried Client.GetLeaders
+func (r *RetryMiddleware) GetLeaders(ctx context.Context, leaderboardID string, pageSize, page int, order string,
+	includePreviousRank bool) ([]*Member, error) {
+	var members []*Member
+	err := r.retry(ctx, "GetLeaders", true, func() (err error) {
+		members, err = r.client.GetLeaders(ctx, leaderboardID, pageSize, page, order, includePreviousRank)
+		return err
+	})
+	return members, err
+}
+
+// GetTopPercentage is a retried Client.GetTopPercentage
+func (r *RetryMiddleware) GetTopPercentage(ctx context.Context, leaderboardID string, pageSize, amount, maxMembers int,
+	order string) ([]*Member, error) {
+	var members []*Member
+	err := r.retry(ctx, "GetTopPercentage", true, func() (err error) {
+		members, err = r.client.GetTopPercentage(ctx, leaderboardID, pageSize, amount, maxMembers, order)
+		return err
+	})
+	return members, err
+}
+
+// RemoveLeaderboard is a retried Client.RemoveLeaderboard
+func (r *RetryMiddleware) RemoveLeaderboard(ctx context.Context, leaderboardID string) error {
+	return r.retry(ctx, "RemoveLeaderboard", true, func() error {
+		return r.client.RemoveLeaderboard(ctx, leaderboardID)
+	})
+}
+
+// Ping is a retried Client.Ping
+func (r *RetryMiddleware) Ping(ctx context.Context) (string, error) {
+	var pong string
+	err := r.retry(ctx, "Ping", true, func() (err error) {
+		pong, err = r.client.Ping(ctx)
+		return err
+	})
+	return pong, err
+}
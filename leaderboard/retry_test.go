@@ -0,0 +1,141 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+	. "github.com/topfreegames/podium/testing"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Retry Middleware", func() {
+	var logger *MockLogger
+	var retriedLeaderboards *RetryMiddleware
+	var faultyRetriedLeaderboards *RetryMiddleware
+	var droppingRetriedLeaderboards *RetryMiddleware
+	var droppingStreakLeaderboards *RetryMiddleware
+	var droppingListener net.Listener
+
+	retryMessages := func() int {
+		count := 0
+		for _, message := range logger.Messages {
+			if message["message"] == "Retrying leaderboard operation." {
+				count++
+			}
+		}
+		return count
+	}
+
+	BeforeEach(func() {
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		faultyRedisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		faultyRedisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+
+		logger = NewMockLogger()
+		retriedLeaderboards = NewRetryLeaderboard(NewClientWithRedis(redisClient), 3, time.Millisecond, logger)
+		faultyRetriedLeaderboards = NewRetryLeaderboard(NewClientWithRedis(faultyRedisClient), 3, time.Millisecond, logger)
+
+		// accepts connections and closes them before answering, leaving commands in an unknown state
+		droppingListener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		go func(listener net.Listener) {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				conn.Close()
+			}
+		}(droppingListener)
+
+		droppingRedisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		droppingRedisClient.Client = redis.NewClient(&redis.Options{Addr: droppingListener.Addr().String()})
+		droppingRetriedLeaderboards = NewRetryLeaderboard(NewClientWithRedis(droppingRedisClient), 3, time.Millisecond,
+			logger)
+		droppingStreakLeaderboards = NewRetryLeaderboard(NewClientWithRedis(droppingRedisClient, WithStreaks()), 3,
+			time.Millisecond, logger)
+	})
+
+	AfterEach(func() {
+		droppingListener.Close()
+	})
+
+	It("should not retry successful operations", func() {
+		lbID := uuid.NewV4().String()
+		member, err := retriedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member", 100, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(member.Score).To(Equal(int64(100)))
+		Expect(retryMessages()).To(BeZero())
+	})
+
+	It("should not retry non transient errors", func() {
+		_, err := retriedLeaderboards.GetMember(NewEmptyCtx(), uuid.NewV4().String(), "invalid-member", "desc", false)
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(&MemberNotFoundError{}))
+		Expect(retryMessages()).To(BeZero())
+	})
+
+	It("should retry network errors up to max retries", func() {
+		_, err := faultyRetriedLeaderboards.SetMemberScore(NewEmptyCtx(), uuid.NewV4().String(), "member", 100, false, "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+		Expect(retryMessages()).To(Equal(3))
+	})
+
+	It("should retry increments when redis can not be reached", func() {
+		_, err := faultyRetriedLeaderboards.IncrementMemberScore(NewEmptyCtx(), uuid.NewV4().String(), "member", 10, "")
+		Expect(err).To(HaveOccurred())
+		Expect(retryMessages()).To(Equal(3))
+	})
+
+	It("should retry idempotent operations when the connection is dropped", func() {
+		_, err := droppingRetriedLeaderboards.SetMemberScore(NewEmptyCtx(), uuid.NewV4().String(), "member", 100,
+			false, "")
+		Expect(err).To(HaveOccurred())
+		Expect(retryMessages()).To(Equal(3))
+	})
+
+	It("should not retry increments when the connection is dropped", func() {
+		_, err := droppingRetriedLeaderboards.IncrementMemberScore(NewEmptyCtx(), uuid.NewV4().String(), "member", 10,
+			"")
+		Expect(err).To(HaveOccurred())
+		Expect(retryMessages()).To(BeZero())
+	})
+
+	It("should not retry sets when the connection is dropped and writes are recorded elsewhere", func() {
+		_, err := droppingStreakLeaderboards.SetMemberScore(NewEmptyCtx(), uuid.NewV4().String(), "member", 100,
+			false, "")
+		Expect(err).To(HaveOccurred())
+
+		err = droppingStreakLeaderboards.SetMembersScore(NewEmptyCtx(), uuid.NewV4().String(), Members{
+			&Member{PublicID: "member", Score: 100},
+		}, false, "")
+		Expect(err).To(HaveOccurred())
+		Expect(retryMessages()).To(BeZero())
+	})
+})
@@ -0,0 +1,136 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+)
+
+// defaultSnapshotTTL is how long a snapshot is kept when no TTL is given
+const defaultSnapshotTTL = time.Minute
+
+// LeaderboardSnapshot is a point-in-time copy of a leaderboard, so paging through it gives consistent
+// ranks while the leaderboard keeps being updated. Snapshots expire after their TTL.
+type LeaderboardSnapshot struct {
+	client        *Client
+	leaderboardID string
+	key           string
+	ExpireAt      time.Time
+}
+
+// TakeSnapshot atomically copies the given leaderboard into a snapshot that expires after snapshotTTL,
+// or after one minute if snapshotTTL is not positive
+func (c *Client) TakeSnapshot(ctx context.Context, leaderboardID string,
+	snapshotTTL time.Duration) (*LeaderboardSnapshot, error) {
+	if snapshotTTL <= 0 {
+		snapshotTTL = defaultSnapshotTTL
+	}
+	key := fmt.Sprintf("%s:snapshot:%s", leaderboardID, uuid.NewV4().String())
+
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	pipe.ZUnionStore(key, redis.ZStore{}, leaderboardID)
+	pipe.Expire(key, snapshotTTL)
+	_, err := pipe.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("Taking leaderboard snapshot failed: %v", err)
+	}
+
+	return &LeaderboardSnapshot{
+		client:        c,
+		leaderboardID: leaderboardID,
+		key:           key,
+		ExpireAt:      time.Now().Add(snapshotTTL),
+	}, nil
+}
+
+// Key returns the redis key holding the snapshot
+func (s *LeaderboardSnapshot) Key() string {
+	return s.key
+}
+
+// LeaderboardID returns the ID of the leaderboard the snapshot was taken from
+func (s *LeaderboardSnapshot) LeaderboardID() string {
+	return s.leaderboardID
+}
+
+// Release removes the snapshot before it expires
+func (s *LeaderboardSnapshot) Release(ctx context.Context) error {
+	err := s.client.redisWithTracing(ctx).Del(s.key).Err()
+	if err != nil {
+		return fmt.Errorf("Release of leaderboard snapshot failed: %v", err)
+	}
+	return nil
+}
+
+func (s *LeaderboardSnapshot) memberNotFound(err error) error {
+	if notFound, ok := err.(*MemberNotFoundError); ok {
+		return NewMemberNotFound(s.leaderboardID, notFound.MemberID)
+	}
+	return err
+}
+
+// TotalMembers returns the number of members in the snapshot
+func (s *LeaderboardSnapshot) TotalMembers(ctx context.Context) (int, error) {
+	return s.client.TotalMembers(ctx, s.key)
+}
+
+// TotalPages returns the number of pages of the snapshot
+func (s *LeaderboardSnapshot) TotalPages(ctx context.Context, pageSize int) (int, error) {
+	return s.client.TotalPages(ctx, s.key, pageSize)
+}
+
+// GetMember returns a member as it was when the snapshot was taken
+func (s *LeaderboardSnapshot) GetMember(ctx context.Context, memberID string, order string) (*Member, error) {
+	member, err := s.client.GetMember(ctx, s.key, memberID, order, false)
+	if err != nil {
+		return nil, s.memberNotFound(err)
+	}
+	return member, nil
+}
+
+// GetMembers returns members as they were when the snapshot was taken
+func (s *LeaderboardSnapshot) GetMembers(ctx context.Context, memberIDs []string, order string) ([]*Member, error) {
+	return s.client.GetMembers(ctx, s.key, memberIDs, order, false, false)
+}
+
+// GetRank returns the rank a member had when the snapshot was taken
+func (s *LeaderboardSnapshot) GetRank(ctx context.Context, memberID string, order string) (int, error) {
+	rank, err := s.client.GetRank(ctx, s.key, memberID, order)
+	if err != nil {
+		return -1, s.memberNotFound(err)
+	}
+	return rank, nil
+}
+
+// GetLeaders returns a page of members of the snapshot
+func (s *LeaderboardSnapshot) GetLeaders(ctx context.Context, pageSize, page int, order string) ([]*Member, error) {
+	return s.client.GetLeaders(ctx, s.key, pageSize, page, order, false)
+}
+
+// GetMembersByRange returns the members of the snapshot between the given 0-based offsets
+func (s *LeaderboardSnapshot) GetMembersByRange(ctx context.Context, startOffset int, endOffset int,
+	order string) ([]*Member, error) {
+	return s.client.GetMembersByRange(ctx, s.key, startOffset, endOffset, order)
+}
+
+// GetAroundMe returns a page of members of the snapshot around the given member
+func (s *LeaderboardSnapshot) GetAroundMe(ctx context.Context, pageSize int, memberID string, order string,
+	getLastIfNotFound bool) ([]*Member, error) {
+	members, err := s.client.GetAroundMe(ctx, s.key, pageSize, memberID, order, getLastIfNotFound)
+	if err != nil {
+		return nil, s.memberNotFound(err)
+	}
+	return members, nil
+}
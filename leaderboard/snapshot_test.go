@@ -0,0 +1,113 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Leaderboard Snapshot", func() {
+	var redisClient *extredis.Client
+	var leaderboards *Client
+	var faultyLeaderboards *Client
+	var lbID string
+
+	BeforeEach(func() {
+		var err error
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		leaderboards = NewClientWithRedis(redisClient)
+
+		faultyRedisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		faultyRedisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		faultyLeaderboards = NewClientWithRedis(faultyRedisClient)
+
+		lbID = uuid.NewV4().String()
+		for i := 0; i < 10; i++ {
+			_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i*10), false, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should not see updates made after the snapshot was taken", func() {
+		snapshot, err := leaderboards.TakeSnapshot(NewEmptyCtx(), lbID, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshot.LeaderboardID()).To(Equal(lbID))
+
+		_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-0", 1000, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-new", 500, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		leaders, err := snapshot.GetLeaders(NewEmptyCtx(), 3, 1, "desc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(leaders).To(HaveLen(3))
+		Expect(leaders[0]).To(Equal(&Member{PublicID: "member-9", Score: 90, Rank: 1}))
+
+		member, err := snapshot.GetMember(NewEmptyCtx(), "member-0", "desc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(member.Score).To(Equal(int64(0)))
+		Expect(member.Rank).To(Equal(10))
+
+		rank, err := snapshot.GetRank(NewEmptyCtx(), "member-9", "desc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rank).To(Equal(1))
+
+		total, err := snapshot.TotalMembers(NewEmptyCtx())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(total).To(Equal(10))
+
+		_, err = snapshot.GetMember(NewEmptyCtx(), "member-new", "desc")
+		Expect(err).To(Equal(NewMemberNotFound(lbID, "member-new")))
+	})
+
+	It("should expire after the snapshot TTL", func() {
+		snapshot, err := leaderboards.TakeSnapshot(NewEmptyCtx(), lbID, 30*time.Second)
+		Expect(err).NotTo(HaveOccurred())
+
+		ttl, err := redisClient.Client.TTL(snapshot.Key()).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ttl).To(BeNumerically(">", 0))
+		Expect(ttl).To(BeNumerically("<=", 30*time.Second))
+		Expect(snapshot.ExpireAt).To(BeTemporally("~", time.Now().Add(30*time.Second), time.Second))
+	})
+
+	It("should be removed when released", func() {
+		snapshot, err := leaderboards.TakeSnapshot(NewEmptyCtx(), lbID, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshot.Release(NewEmptyCtx())).To(Succeed())
+
+		exists, err := redisClient.Client.Exists(snapshot.Key()).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeZero())
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		_, err := faultyLeaderboards.TakeSnapshot(NewEmptyCtx(), lbID, time.Minute)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
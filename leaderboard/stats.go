@@ -0,0 +1,92 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+	"github.com/topfreegames/extensions/redis/interfaces"
+	"github.com/topfreegames/podium/log"
+	"go.uber.org/zap"
+)
+
+// CrossLeaderboardStats are statistics aggregated over several leaderboards
+type CrossLeaderboardStats struct {
+	TotalUniqueMembers int64            `json:"totalUniqueMembers"`
+	TotalScoreVolume   int64            `json:"totalScoreVolume"`
+	MemberCounts       map[string]int64 `json:"memberCounts"`
+}
+
+// crossStatsBatchSize is how many members are read at a time when summing the scores of a leaderboard
+const crossStatsBatchSize = 1000
+
+// GetCrossLeaderboardStats returns how many distinct members are in any of the given leaderboards, the
+// sum of all their scores and how many members each leaderboard has. The member counts are computed by
+// redis in a single script run, while the score volume is summed as integers from the leaderboards read
+// in batches of crossStatsBatchSize, so it is not a point in time snapshot if they change meanwhile.
+func GetCrossLeaderboardStats(redisClient interfaces.RedisClient, leaderboardIDs []string,
+	logger zap.Logger) (*CrossLeaderboardStats, error) {
+	if len(leaderboardIDs) == 0 {
+		return nil, fmt.Errorf("At least one leaderboard must be provided.")
+	}
+
+	script := redis.NewScript(`
+		-- Script params:
+		-- KEYS[1] is the name of the temporary union of the leaderboards
+		-- KEYS[2..n] are the names of the leaderboards
+
+		local counts = {}
+		for i = 2, #KEYS do
+			table.insert(counts, redis.call("ZCARD", KEYS[i]))
+		end
+
+		local unique = redis.call("ZUNIONSTORE", KEYS[1], #KEYS - 1, unpack(KEYS, 2))
+		redis.call("DEL", KEYS[1])
+
+		return {unique, counts}
+	`)
+
+	keys := append([]string{fmt.Sprintf("union:%s", uuid.NewV4().String())}, leaderboardIDs...)
+	result, err := script.Run(redisClient, keys).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Retrieval of cross leaderboard stats failed: %v", err)
+	}
+
+	values := result.([]interface{})
+	stats := &CrossLeaderboardStats{
+		TotalUniqueMembers: values[0].(int64),
+		MemberCounts:       make(map[string]int64, len(leaderboardIDs)),
+	}
+	for i, count := range values[1].([]interface{}) {
+		stats.MemberCounts[leaderboardIDs[i]] = count.(int64)
+	}
+
+	for _, leaderboardID := range leaderboardIDs {
+		for start := int64(0); ; start += crossStatsBatchSize {
+			members, err := redisClient.ZRangeWithScores(leaderboardID, start, start+crossStatsBatchSize-1).Result()
+			if err != nil {
+				return nil, fmt.Errorf("Retrieval of cross leaderboard stats failed: %v", err)
+			}
+			for _, member := range members {
+				stats.TotalScoreVolume += int64(member.Score)
+			}
+			if len(members) < crossStatsBatchSize {
+				break
+			}
+		}
+	}
+
+	log.D(logger, "Cross leaderboard stats retrieved.", func(cm log.CM) {
+		cm.Write(zap.Int("leaderboards", len(leaderboardIDs)), zap.Int64("uniqueMembers", stats.TotalUniqueMembers))
+	})
+	return stats, nil
+}
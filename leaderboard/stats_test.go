@@ -0,0 +1,96 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+	. "github.com/topfreegames/podium/testing"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Cross Leaderboard Stats", func() {
+	var redisClient *extredis.Client
+	var firstLbID, secondLbID string
+
+	BeforeEach(func() {
+		var err error
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err = extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		leaderboards := NewClientWithRedis(redisClient)
+
+		firstLbID = uuid.NewV4().String()
+		secondLbID = uuid.NewV4().String()
+		scores := map[string]map[string]int64{
+			firstLbID:  {"member-1": 10, "member-2": 20, "member-3": 30},
+			secondLbID: {"member-3": 100, "member-4": 200},
+		}
+		for lbID, members := range scores {
+			for memberID, score := range members {
+				_, err := leaderboards.SetMemberScore(NewEmptyCtx(), lbID, memberID, score, false, "")
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+	})
+
+	It("should aggregate stats of all leaderboards", func() {
+		emptyLbID := uuid.NewV4().String()
+		stats, err := GetCrossLeaderboardStats(redisClient.Client, []string{firstLbID, secondLbID, emptyLbID},
+			NewMockLogger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(Equal(&CrossLeaderboardStats{
+			TotalUniqueMembers: 4,
+			TotalScoreVolume:   360,
+			MemberCounts:       map[string]int64{firstLbID: 3, secondLbID: 2, emptyLbID: 0},
+		}))
+	})
+
+	It("should sum large leaderboards and scores exactly", func() {
+		lbID := uuid.NewV4().String()
+		members := Members{}
+		for i := 0; i < 1200; i++ {
+			members = append(members, &Member{PublicID: fmt.Sprintf("member-%d", i), Score: 1})
+		}
+		for i := 0; i < 3; i++ {
+			members = append(members, &Member{PublicID: fmt.Sprintf("big-member-%d", i), Score: 1<<52 + 1})
+		}
+		err := NewClientWithRedis(redisClient).SetMembersScore(NewEmptyCtx(), lbID, members, false, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		stats, err := GetCrossLeaderboardStats(redisClient.Client, []string{lbID}, NewMockLogger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.TotalUniqueMembers).To(Equal(int64(1203)))
+		Expect(stats.TotalScoreVolume).To(Equal(int64(1200 + 3*(1<<52+1))))
+	})
+
+	It("should fail without leaderboards", func() {
+		_, err := GetCrossLeaderboardStats(redisClient.Client, []string{}, NewMockLogger())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if invalid connection to Redis", func() {
+		faultyClient := redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		_, err := GetCrossLeaderboardStats(faultyClient, []string{firstLbID}, NewMockLogger())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
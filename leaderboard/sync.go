@@ -0,0 +1,117 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis"
+)
+
+// defaultSyncBatchSize is the number of members written at a time by SyncFromJSON when no batch size is given
+const defaultSyncBatchSize = 1000
+
+// SyncResult counts what happened to each member given to SyncFromJSON
+type SyncResult struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// SyncFromJSON imports the members in jsonData, a JSON array of members with publicID and score, into the
+// leaderboard. With the "overwrite" conflict policy scores of existing members are replaced, while with
+// "keep_higher" they are only replaced by higher scores. Members are written batchSize at a time, and
+// members of batches that could not be written, or without a public ID, are counted as failed.
+func (c *Client) SyncFromJSON(ctx context.Context, leaderboardID string, jsonData []byte, conflictPolicy string,
+	batchSize int) (*SyncResult, error) {
+	if conflictPolicy != "overwrite" && conflictPolicy != "keep_higher" {
+		return nil, fmt.Errorf("Invalid conflict policy %s, must be overwrite or keep_higher", conflictPolicy)
+	}
+	if batchSize <= 0 {
+		batchSize = defaultSyncBatchSize
+	}
+
+	var members Members
+	err := json.Unmarshal(jsonData, &members)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid members JSON: %v", err)
+	}
+
+	result := &SyncResult{}
+	valid := make(Members, 0, len(members))
+	for _, member := range members {
+		if member == nil || member.PublicID == "" {
+			result.Failed++
+			continue
+		}
+		valid = append(valid, &Member{PublicID: member.PublicID, Score: member.Score})
+	}
+
+	for start := 0; start < len(valid); start += batchSize {
+		end := start + batchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+
+		existing, err := c.existingMembers(ctx, leaderboardID, batch)
+		if err != nil {
+			result.Failed += len(batch)
+			continue
+		}
+
+		updated := batch
+		if conflictPolicy == "keep_higher" {
+			partial, err := c.SetMembersScoreIfHigher(ctx, leaderboardID, batch, false, "")
+			if err != nil {
+				result.Failed += len(batch)
+				continue
+			}
+			updated = partial.Updated
+			result.Skipped += len(partial.Skipped)
+		} else {
+			err = c.SetMembersScore(ctx, leaderboardID, batch, false, "")
+			if err != nil {
+				result.Failed += len(batch)
+				continue
+			}
+		}
+
+		for _, member := range updated {
+			if existing[member.PublicID] {
+				result.Updated++
+			} else {
+				result.Added++
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) existingMembers(ctx context.Context, leaderboardID string, members Members) (map[string]bool, error) {
+	pipe := c.redisWithTracing(ctx).TxPipeline()
+	cmds := make([]*redis.FloatCmd, len(members))
+	for i, member := range members {
+		cmds[i] = pipe.ZScore(leaderboardID, member.PublicID)
+	}
+	_, err := pipe.Exec()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Retrieval of members failed: %v", err)
+	}
+
+	existing := make(map[string]bool, len(members))
+	for i, member := range members {
+		existing[member.PublicID] = cmds[i].Err() == nil
+	}
+	return existing, nil
+}
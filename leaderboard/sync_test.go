@@ -0,0 +1,93 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"github.com/go-redis/redis"
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Leaderboard Sync", func() {
+	var leaderboards *Client
+	var faultyLeaderboards *Client
+	var lbID string
+	jsonData := []byte(`[
+		{"publicID": "member-1", "score": 50},
+		{"publicID": "member-2", "score": 300},
+		{"publicID": "member-3", "score": 30},
+		{"score": 10}
+	]`)
+
+	BeforeEach(func() {
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		leaderboards = NewClientWithRedis(redisClient)
+
+		faultyRedisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+		faultyRedisClient.Client = redis.NewClient(&redis.Options{Addr: "localhost:1235"})
+		faultyLeaderboards = NewClientWithRedis(faultyRedisClient)
+
+		lbID = uuid.NewV4().String()
+		_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-1", 100, false, "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = leaderboards.SetMemberScore(NewEmptyCtx(), lbID, "member-2", 200, false, "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should overwrite existing scores", func() {
+		result, err := leaderboards.SyncFromJSON(NewEmptyCtx(), lbID, jsonData, "overwrite", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&SyncResult{Added: 1, Updated: 2, Skipped: 0, Failed: 1}))
+
+		member, err := leaderboards.GetMember(NewEmptyCtx(), lbID, "member-1", "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(member.Score).To(Equal(int64(50)))
+	})
+
+	It("should keep higher existing scores", func() {
+		result, err := leaderboards.SyncFromJSON(NewEmptyCtx(), lbID, jsonData, "keep_higher", 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&SyncResult{Added: 1, Updated: 1, Skipped: 1, Failed: 1}))
+
+		expected := map[string]int64{"member-1": 100, "member-2": 300, "member-3": 30}
+		for memberID, score := range expected {
+			member, err := leaderboards.GetMember(NewEmptyCtx(), lbID, memberID, "desc", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(member.Score).To(Equal(score))
+		}
+	})
+
+	It("should fail with invalid conflict policy or JSON", func() {
+		_, err := leaderboards.SyncFromJSON(NewEmptyCtx(), lbID, jsonData, "keep_lower", 0)
+		Expect(err).To(HaveOccurred())
+
+		_, err = leaderboards.SyncFromJSON(NewEmptyCtx(), lbID, []byte(`{"publicID": "member-1"}`), "overwrite", 0)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid members JSON"))
+	})
+
+	It("should count members as failed if invalid connection to Redis", func() {
+		result, err := faultyLeaderboards.SyncFromJSON(NewEmptyCtx(), lbID, jsonData, "overwrite", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&SyncResult{Failed: 4}))
+	})
+})
@@ -0,0 +1,211 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// histogramReservoirSize is the number of most recent durations kept to compute percentiles
+const histogramReservoirSize = 1024
+
+// HistogramStats keeps the timing of a single operation. Count and mean cover every call, while
+// percentiles are computed over a sliding window of the most recent histogramReservoirSize calls, however
+// old they are. Durations are not decayed over time, so an operation that stops being called keeps
+// reporting the percentiles of its last calls, and a burst of more calls than the window replaces them all.
+type HistogramStats struct {
+	mutex     sync.Mutex
+	count     int64
+	total     time.Duration
+	reservoir []time.Duration
+}
+
+func (h *HistogramStats) record(duration time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(h.reservoir) < histogramReservoirSize {
+		h.reservoir = append(h.reservoir, duration)
+	} else {
+		h.reservoir[h.count%histogramReservoirSize] = duration
+	}
+	h.count++
+	h.total += duration
+}
+
+func (h *HistogramStats) stats() (int64, time.Duration, time.Duration, time.Duration, time.Duration) {
+	h.mutex.Lock()
+	count, total := h.count, h.total
+	durations := make([]time.Duration, len(h.reservoir))
+	copy(durations, h.reservoir)
+	h.mutex.Unlock()
+
+	if count == 0 {
+		return 0, 0, 0, 0, 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	percentile := func(p float64) time.Duration {
+		return durations[int(p*float64(len(durations)-1))]
+	}
+	return count, total / time.Duration(count), percentile(0.5), percentile(0.95), percentile(0.99)
+}
+
+// TimingMiddleware wraps a Client recording how long each leaderboard operation takes. Like RetryMiddleware,
+// it only exposes the timed operations: GetMembersByRange, IncrementMemberScore, SetMemberScore,
+// SetMembersScore, TotalMembers, RemoveMembers, RemoveMember, TotalPages, GetMember, GetMembers, GetAroundMe,
+// GetAroundScore, GetRank, GetLeaders, GetTopPercentage, RemoveLeaderboard and Ping. Other operations must be
+// called on the wrapped client and are not timed.
+type TimingMiddleware struct {
+	client     *Client
+	histograms sync.Map
+}
+
+// NewTimingMiddleware returns a TimingMiddleware that wraps the given client
+func NewTimingMiddleware(client *Client) *TimingMiddleware {
+	return &TimingMiddleware{client: client}
+}
+
+func (t *TimingMiddleware) observe(operation string, start time.Time) {
+	histogram, _ := t.histograms.LoadOrStore(operation, &HistogramStats{})
+	histogram.(*HistogramStats).record(time.Since(start))
+}
+
+// GetOperationStats returns the number of calls and the mean, p50, p95 and p99 durations of the given
+// operation, named after the wrapped method. The percentiles only cover the last histogramReservoirSize
+// calls, see HistogramStats. All values are zero if the operation was never called.
+func (t *TimingMiddleware) GetOperationStats(operation string) (int64, time.Duration, time.Duration, time.Duration,
+	time.Duration) {
+	histogram, ok := t.histograms.Load(operation)
+	if !ok {
+		return 0, 0, 0, 0, 0
+	}
+	return histogram.(*HistogramStats).stats()
+}
+
+// GetLeaderboardP99Latency returns the p99 duration of the given operation
+func (t *TimingMiddleware) GetLeaderboardP99Latency(operation string) time.Duration {
+	_, _, _, _, p99 := t.GetOperationStats(operation)
+	return p99
+}
+
+// GetMembersByRange is a timed Client.GetMembersByRange
+func (t *TimingMiddleware) GetMembersByRange(ctx context.Context, leaderboard string, startOffset int, endOffset int,
+	order string) ([]*Member, error) {
+	defer t.observe("GetMembersByRange", time.Now())
+	return t.client.GetMembersByRange(ctx, leaderboard, startOffset, endOffset, order)
+}
+
+// IncrementMemberScore is a timed Client.IncrementMemberScore
+func (t *TimingMiddleware) IncrementMemberScore(ctx context.Context, leaderboardID string, memberID string,
+	increment int, scoreTTL string) (*Member, error) {
+	defer t.observe("IncrementMemberScore", time.Now())
+	return t.client.IncrementMemberScore(ctx, leaderboardID, memberID, increment, scoreTTL)
+}
+
+// SetMemberScore is a timed Client.SetMemberScore
+func (t *TimingMiddleware) SetMemberScore(ctx context.Context, leaderboardID string, memberID string, score int64,
+	prevRank bool, scoreTTL string) (*Member, error) {
+	defer t.observe("SetMemberScore", time.Now())
+	return t.client.SetMemberScore(ctx, leaderboardID, memberID, score, prevRank, scoreTTL)
+}
+
+// SetMembersScore is a timed Client.SetMembersScore
+func (t *TimingMiddleware) SetMembersScore(ctx context.Context, leaderboardID string, members Members, prevRank bool,
+	scoreTTL string) error {
+	defer t.observe("SetMembersScore", time.Now())
+	return t.client.SetMembersScore(ctx, leaderboardID, members, prevRank, scoreTTL)
+}
+
+// TotalMembers is a timed Client.TotalMembers
+func (t *TimingMiddleware) TotalMembers(ctx context.Context, leaderboardID string) (int, error) {
+	defer t.observe("TotalMembers", time.Now())
+	return t.client.TotalMembers(ctx, leaderboardID)
+}
+
+// RemoveMembers is a timed Client.RemoveMembers
+func (t *TimingMiddleware) RemoveMembers(ctx context.Context, leaderboardID string, memberIDs []interface{}) error {
+	defer t.observe("RemoveMembers", time.Now())
+	return t.client.RemoveMembers(ctx, leaderboardID, memberIDs)
+}
+
+// RemoveMember is a timed Client.RemoveMember
+func (t *TimingMiddleware) RemoveMember(ctx context.Context, leaderboardID string, memberID string) error {
+	defer t.observe("RemoveMember", time.Now())
+	return t.client.RemoveMember(ctx, leaderboardID, memberID)
+}
+
+// TotalPages is a timed Client.TotalPages
+func (t *TimingMiddleware) TotalPages(ctx context.Context, leaderboardID string, pageSize int) (int, error) {
+	defer t.observe("TotalPages", time.Now())
+	return t.client.TotalPages(ctx, leaderboardID, pageSize)
+}
+
+// GetMember is a timed Client.GetMember
+func (t *TimingMiddleware) GetMember(ctx context.Context, leaderboardID string, memberID string, order string,
+	includeTTL bool) (*Member, error) {
+	defer t.observe("GetMember", time.Now())
+	return t.client.GetMember(ctx, leaderboardID, memberID, order, includeTTL)
+}
+
+// GetMembers is a timed Client.GetMembers
+func (t *TimingMiddleware) GetMembers(ctx context.Context, leaderboardID string, memberIDs []string, order string,
+	includeTTL bool, includePreviousRank bool) ([]*Member, error) {
+	defer t.observe("GetMembers", time.Now())
+	return t.client.GetMembers(ctx, leaderboardID, memberIDs, order, includeTTL, includePreviousRank)
+}
+
+// GetAroundMe is a timed Client.GetAroundMe
+func (t *TimingMiddleware) GetAroundMe(ctx context.Context, leaderboardID string, pageSize int, memberID string,
+	order string, getLastIfNotFound bool) ([]*Member, error) {
+	defer t.observe("GetAroundMe", time.Now())
+	return t.client.GetAroundMe(ctx, leaderboardID, pageSize, memberID, order, getLastIfNotFound)
+}
+
+// GetAroundScore is a timed Client.GetAroundScore
+func (t *TimingMiddleware) GetAroundScore(ctx context.Context, leaderboardID string, pageSize int, score int64,
+	order string) ([]*Member, error) {
+	defer t.observe("GetAroundScore", time.Now())
+	return t.client.GetAroundScore(ctx, leaderboardID, pageSize, score, order)
+}
+
+// GetRank is a timed Client.GetRank
+func (t *TimingMiddleware) GetRank(ctx context.Context, leaderboardID string, memberID string, order string) (int, error) {
+	defer t.observe("GetRank", time.Now())
+	return t.client.GetRank(ctx, leaderboardID, memberID, order)
+}
+
+// GetLeaders is a timed Client.GetLeaders
+func (t *TimingMiddleware) GetLeaders(ctx context.Context, leaderboardID string, pageSize, page int, order string,
+	includePreviousRank bool) ([]*Member, error) {
+	defer t.observe("GetLeaders", time.Now())
+	return t.client.GetLeaders(ctx, leaderboardID, pageSize, page, order, includePreviousRank)
+}
+
+// GetTopPercentage is a timed Client.GetTopPercentage
+func (t *TimingMiddleware) GetTopPercentage(ctx context.Context, leaderboardID string, pageSize, amount, maxMembers int,
+	order string) ([]*Member, error) {
+	defer t.observe("GetTopPercentage", time.Now())
+	return t.client.GetTopPercentage(ctx, leaderboardID, pageSize, amount, maxMembers, order)
+}
+
+// RemoveLeaderboard is a timed Client.RemoveLeaderboard
+func (t *TimingMiddleware) RemoveLeaderboard(ctx context.Context, leaderboardID string) error {
+	defer t.observe("RemoveLeaderboard", time.Now())
+	return t.client.RemoveLeaderboard(ctx, leaderboardID)
+}
+
+// Ping is a timed Client.Ping
+func (t *TimingMiddleware) Ping(ctx context.Context) (string, error) {
+	defer t.observe("Ping", time.Now())
+	return t.client.Ping(ctx)
+}
@@ -0,0 +1,76 @@
+// podium
+// https://github.com/topfreegames/podium
+// Licensed under the MIT license:
+// http://www.opensource.org/licenses/mit-license
+// Copyright © 2016 Top Free Games <backend@tfgco.com>
+// Forked from
+// https://github.com/dayvson/go-leaderboard
+// Copyright © 2013 Maxwell Dayvson da Silva
+
+package leaderboard_test
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/topfreegames/podium/leaderboard"
+
+	uuid "github.com/satori/go.uuid"
+	extredis "github.com/topfreegames/extensions/redis"
+)
+
+var _ = Describe("Timing Middleware", func() {
+	var timedLeaderboards *TimingMiddleware
+
+	BeforeEach(func() {
+		config := viper.New()
+		config.Set("redis.url", "redis://localhost:1234/0")
+		config.Set("redis.connectionTimeout", 200)
+
+		redisClient, err := extredis.NewClient("redis", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		timedLeaderboards = NewTimingMiddleware(NewClientWithRedis(redisClient))
+	})
+
+	It("should record stats of each operation", func() {
+		lbID := uuid.NewV4().String()
+		for i := 0; i < 10; i++ {
+			_, err := timedLeaderboards.SetMemberScore(NewEmptyCtx(), lbID, fmt.Sprintf("member-%d", i), int64(i), false, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+		_, err := timedLeaderboards.GetLeaders(NewEmptyCtx(), lbID, 5, 1, "desc", false)
+		Expect(err).NotTo(HaveOccurred())
+
+		count, mean, p50, p95, p99 := timedLeaderboards.GetOperationStats("SetMemberScore")
+		Expect(count).To(Equal(int64(10)))
+		Expect(mean).To(BeNumerically(">", 0))
+		Expect(p50).To(BeNumerically(">", 0))
+		Expect(p95).To(BeNumerically(">=", p50))
+		Expect(p99).To(BeNumerically(">=", p95))
+		Expect(timedLeaderboards.GetLeaderboardP99Latency("SetMemberScore")).To(Equal(p99))
+
+		count, _, _, _, _ = timedLeaderboards.GetOperationStats("GetLeaders")
+		Expect(count).To(Equal(int64(1)))
+	})
+
+	It("should return zero stats for operations never called", func() {
+		count, mean, p50, p95, p99 := timedLeaderboards.GetOperationStats("GetMember")
+		Expect(count).To(BeZero())
+		Expect(mean).To(BeZero())
+		Expect(p50).To(BeZero())
+		Expect(p95).To(BeZero())
+		Expect(p99).To(BeZero())
+	})
+
+	It("should record failed operations", func() {
+		_, err := timedLeaderboards.GetMember(NewEmptyCtx(), uuid.NewV4().String(), "invalid-member", "desc", false)
+		Expect(err).To(HaveOccurred())
+
+		count, _, _, _, _ := timedLeaderboards.GetOperationStats("GetMember")
+		Expect(count).To(Equal(int64(1)))
+	})
+})